@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"time"
@@ -10,10 +11,6 @@ import (
 )
 
 var (
-	clientID        string
-	buildID         string
-	accessKey       string
-	secretKey       string
 	intakeBaseURL   string
 	pogrJWT         string
 	twitchID        string
@@ -26,10 +23,6 @@ func init() {
 		log.Printf("Warning: .env file not found: %v", err)
 	}
 
-	clientID = os.Getenv("POGR_CLIENT_ID")
-	buildID = os.Getenv("POGR_BUILD_ID")
-	accessKey = os.Getenv("POGR_ACCESS_KEY")
-	secretKey = os.Getenv("POGR_SECRET_KEY")
 	intakeBaseURL = os.Getenv("INTAKE_BASE_URL")
 	pogrJWT = os.Getenv("POGR_JWT")
 	twitchID = os.Getenv("TWITCH_ID")
@@ -38,14 +31,21 @@ func init() {
 }
 
 func main() {
-	config := pogr.Config{
-		ClientKey:            clientID,
-		BuildKey:             buildID,
-		AccessKey:            accessKey,
-		SecretKey:            secretKey,
-		BaseURL:              intakeBaseURL,
-		Timeout:              30 * time.Second,
-		EnableConnectionPool: true,
+	// Reads POGR_CLIENT_ID, POGR_BUILD_ID, POGR_ACCESS_KEY, POGR_SECRET_KEY, ...
+	config, err := pogr.ConfigFromEnv("POGR")
+	if err != nil {
+		log.Printf("Configuration problems:\n%v", err)
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = intakeBaseURL
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	config.EnableConnectionPool = true
+	config.EnableRetries = true
+	config.Interceptors = []pogr.Interceptor{
+		pogr.UserAgentInterceptor("pogr-example"),
 	}
 
 	sdk := pogr.NewPOGRSDK(config)
@@ -53,55 +53,75 @@ func main() {
 	log.Printf("SDK Configuration:\n%s", sdk.PrintConfig())
 
 	// Authentication examples
-	runJWTExample(sdk)
-	runAssociationIDExample(sdk)
-	runSteamTicketExample(sdk)
+	sessions := []*pogr.Session{
+		runJWTExample(sdk),
+		runAssociationIDExample(sdk),
+		runSteamTicketExample(sdk),
+	}
 
-	// Data example
-	sendTestData(sdk, "Session-based auth")
+	// Data examples
+	sendTestData(sdk, "Key-based auth")
+	for _, session := range sessions {
+		if session != nil {
+			sendTestData(session, "Session-based auth")
+		}
+	}
 
 	// Example usage of additional endpoints
 	runEventExample(sdk)
 	runLogExample(sdk)
 	runMetricsExample(sdk)
 	runMonitorExample(sdk)
+
+	// Close flushes pending work and ends every open session
+	if err := sdk.Close(context.Background()); err != nil {
+		log.Printf("Failed to close SDK: %v", err)
+	}
 }
 
-func runJWTExample(sdk pogr.POGRService) {
-	sessionID, err := sdk.InitWithUserJWT(pogrJWT)
+func runJWTExample(sdk pogr.POGRService) *pogr.Session {
+	session, err := sdk.InitWithUserJWT(pogrJWT)
 	if err != nil {
 		log.Printf("JWT initialization failed: %v", err)
-		return
+		return nil
 	}
-	log.Printf("JWT Session initialized: %s", sessionID)
+	log.Printf("JWT Session initialized: %s", session.ID())
+	return session
 }
 
-func runAssociationIDExample(sdk pogr.POGRService) {
-	sessionID, err := sdk.InitWithAssociationID(associationID)
+func runAssociationIDExample(sdk pogr.POGRService) *pogr.Session {
+	session, err := sdk.InitWithAssociationID(associationID)
 	if err != nil {
 		log.Printf("Association ID initialization failed: %v", err)
-		return
+		return nil
 	}
-	log.Printf("Association ID Session initialized: %s", sessionID)
+	log.Printf("Association ID Session initialized: %s", session.ID())
+	return session
 }
 
-func runSteamTicketExample(sdk pogr.POGRService) {
-	sessionID, err := sdk.InitWithSteamTicket(steamAuthTicket)
+func runSteamTicketExample(sdk pogr.POGRService) *pogr.Session {
+	session, err := sdk.InitWithSteamTicket(steamAuthTicket)
 	if err != nil {
 		log.Printf("Steam Ticket initialization failed: %v", err)
-		return
+		return nil
 	}
-	log.Printf("Steam Ticket Session initialized: %s", sessionID)
+	log.Printf("Steam Ticket Session initialized: %s", session.ID())
+	return session
 }
 
-func sendTestData(sdk pogr.POGRService, authMethod string) {
+// dataSender is implemented by both the client and its sessions
+type dataSender interface {
+	SendData(data interface{}, tags *pogr.Tags) (string, error)
+}
+
+func sendTestData(sender dataSender, authMethod string) {
 	data := map[string]interface{}{
 		"auth_method": authMethod,
 		"timestamp":   time.Now().Unix(),
 		"test_data":   "Hello POGR!",
 	}
 
-	dataID, err := sdk.SendData(data, nil)
+	dataID, err := sender.SendData(data, nil)
 	if err != nil {
 		log.Printf("Failed to send data with %s: %v", authMethod, err)
 		return
@@ -110,16 +130,21 @@ func sendTestData(sdk pogr.POGRService, authMethod string) {
 }
 
 func runEventExample(sdk pogr.POGRService) {
-	eventData := map[string]interface{}{
-		"player_id":        "12345",
-		"achievement_name": "Master Explorer",
-	}
-
-	tags := &pogr.Tags{
-		DiscordID: "9480bc67-88e6-42ee-bfb6-0c70137d1fad",
+	event, err := pogr.NewEvent("player_login").
+		Sub("level_up").
+		Type("achievement").
+		Flag("completed").
+		Key("level_5_unlocked").
+		Data("player_id", "12345").
+		Data("achievement_name", "Master Explorer").
+		Tags(&pogr.Tags{DiscordID: "9480bc67-88e6-42ee-bfb6-0c70137d1fad"}).
+		Build()
+	if err != nil {
+		log.Printf("Failed to build event: %v", err)
+		return
 	}
 
-	eventID, err := sdk.SendEvent("player_login", "level_up", "achievement", "completed", "level_5_unlocked", eventData, tags)
+	eventID, err := sdk.SendEventObject(event)
 	if err != nil {
 		log.Printf("Failed to send event: %v", err)
 		return
@@ -0,0 +1,234 @@
+package pogr
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AsyncConfig holds settings for the asynchronous send pipeline
+type AsyncConfig struct {
+	BufferSize    int                // Maximum queued items before Send* returns ErrQueueFull
+	BatchSize     int                // Queued items that trigger an immediate flush
+	FlushInterval time.Duration      // Maximum time an item waits before being flushed
+	Results       chan<- AsyncResult // Optional; receives the outcome of every item, dropping results that do not fit
+}
+
+// AsyncResult reports the outcome of an item sent by the async pipeline
+type AsyncResult struct {
+	ItemID   string // Local ID returned by the Send* call that queued the item
+	Endpoint string
+	Payload  []byte
+	DataID   string // Data ID assigned by the intake
+	Err      error
+	Dropped  int // Results dropped since the previous one because Results was full
+}
+
+// DefaultAsyncConfig returns default async pipeline settings
+func DefaultAsyncConfig() *AsyncConfig {
+	return &AsyncConfig{
+		BufferSize:    1000,
+		BatchSize:     50,
+		FlushInterval: time.Second,
+	}
+}
+
+// asyncItem is a marshaled payload waiting to be sent
+type asyncItem struct {
+	id       string
+	session  *Session
+	endpoint string
+	body     []byte
+}
+
+// asyncPipeline buffers payloads and sends them from a background worker
+type asyncPipeline struct {
+	sdk     *pogrSDK
+	config  *AsyncConfig
+	queue   chan asyncItem
+	flushes chan chan struct{} // Flush requests; the worker closes each once everything queued before it was sent
+	stopped chan struct{}
+	nextID  atomic.Uint64
+	dropped int // Results not delivered since the last one that was, owned by the worker
+
+	mu     sync.RWMutex // Guards closed and sends on queue
+	closed bool
+}
+
+// newAsyncPipelineConfig fills unset async settings with defaults
+func newAsyncPipelineConfig(config *AsyncConfig) *AsyncConfig {
+	settings := DefaultAsyncConfig()
+	if config != nil {
+		settings.Results = config.Results
+		if config.BufferSize > 0 {
+			settings.BufferSize = config.BufferSize
+		}
+		if config.BatchSize > 0 {
+			settings.BatchSize = config.BatchSize
+		}
+		if config.FlushInterval > 0 {
+			settings.FlushInterval = config.FlushInterval
+		}
+	}
+	return settings
+}
+
+func newAsyncPipeline(sdk *pogrSDK, config *AsyncConfig) *asyncPipeline {
+	config = newAsyncPipelineConfig(config)
+
+	p := &asyncPipeline{
+		sdk:     sdk,
+		config:  config,
+		queue:   make(chan asyncItem, config.BufferSize),
+		flushes: make(chan chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// enqueue adds an item to the buffer without blocking and returns its local item ID
+func (p *asyncPipeline) enqueue(item asyncItem) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return "", ErrClosed
+	}
+
+	item.id = "async-" + strconv.FormatUint(p.nextID.Add(1), 10)
+	select {
+	case p.queue <- item:
+		return item.id, nil
+	default:
+		return "", ErrQueueFull
+	}
+}
+
+// flush sends everything queued so far and waits for it to complete. When ctx ends first
+// the worker keeps sending in the background; only the wait is abandoned.
+func (p *asyncPipeline) flush(ctx context.Context) error {
+	done := make(chan struct{})
+
+	select {
+	case p.flushes <- done:
+	case <-p.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close stops accepting items, drains the buffer and stops the worker
+func (p *asyncPipeline) close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	if err := p.flush(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case <-p.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run is the background worker that flushes by size or interval
+func (p *asyncPipeline) run() {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]asyncItem, 0, p.config.BatchSize)
+	for {
+		select {
+		case item, ok := <-p.queue:
+			if !ok {
+				p.send(batch)
+				return
+			}
+			batch = append(batch, item)
+			if len(batch) >= p.config.BatchSize {
+				p.send(batch)
+				batch = batch[:0]
+			}
+
+		case <-ticker.C:
+			p.send(batch)
+			batch = batch[:0]
+
+		case done := <-p.flushes:
+			var open bool
+			batch, open = p.drain(batch)
+			p.send(batch)
+			close(done)
+			if !open {
+				return
+			}
+			batch = batch[:0]
+		}
+	}
+}
+
+// drain moves every currently queued item into the batch and reports whether the queue is still open
+func (p *asyncPipeline) drain(batch []asyncItem) ([]asyncItem, bool) {
+	for {
+		select {
+		case item, ok := <-p.queue:
+			if !ok {
+				return batch, false
+			}
+			batch = append(batch, item)
+		default:
+			return batch, true
+		}
+	}
+}
+
+// send delivers a batch item by item and reports each outcome. It never uses a
+// Flush or Close context, so a caller's deadline cannot fail items it did not reach.
+func (p *asyncPipeline) send(batch []asyncItem) {
+	for _, item := range batch {
+		dataID, err := p.sdk.deliver(context.Background(), item.session, item.endpoint, item.body)
+		p.report(AsyncResult{
+			ItemID:   item.id,
+			Endpoint: item.endpoint,
+			Payload:  item.body,
+			DataID:   dataID,
+			Err:      err,
+		})
+	}
+}
+
+// report hands a result to the Results channel without blocking the worker, counting
+// the results that do not fit
+func (p *asyncPipeline) report(result AsyncResult) {
+	if p.config.Results == nil {
+		return
+	}
+
+	result.Dropped = p.dropped
+	select {
+	case p.config.Results <- result:
+		p.dropped = 0
+	default:
+		p.dropped++
+	}
+}
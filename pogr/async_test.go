@@ -0,0 +1,182 @@
+package pogr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+// slowAsync only flushes on demand so tests control delivery
+func slowAsync(config *pogr.Config) {
+	config.EnableAsync = true
+	config.AsyncConfig = &pogr.AsyncConfig{
+		BufferSize:    100,
+		BatchSize:     100,
+		FlushInterval: time.Hour,
+	}
+}
+
+func TestAsyncFlushDeliversQueuedPayloads(t *testing.T) {
+	srv, sdk := newTestClient(t, slowAsync)
+	defer sdk.Close(context.Background())
+
+	for i := range 10 {
+		if _, err := sdk.SendData(map[string]int{"seq": i}, nil); err != nil {
+			t.Fatalf("SendData %d: %v", i, err)
+		}
+	}
+	if got := len(srv.RequestsTo("/data")); got != 0 {
+		t.Fatalf("got %d requests before Flush, want 0", got)
+	}
+
+	if err := sdk.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	requests := srv.RequestsTo("/data")
+	if len(requests) != 10 {
+		t.Fatalf("got %d requests after Flush, want 10", len(requests))
+	}
+	for i, req := range requests {
+		data, _ := req.Payload["data"].(map[string]interface{})
+		if seq, _ := data["seq"].(float64); int(seq) != i {
+			t.Errorf("request %d carried seq %v, want payloads in send order", i, data["seq"])
+		}
+	}
+}
+
+func TestAsyncCloseDrainsQueue(t *testing.T) {
+	results := make(chan pogr.AsyncResult, 10)
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		slowAsync(config)
+		config.AsyncConfig.Results = results
+	})
+
+	itemIDs := make(map[string]bool)
+	for i := range 5 {
+		itemID, err := sdk.SendData(map[string]int{"seq": i}, nil)
+		if err != nil {
+			t.Fatalf("SendData %d: %v", i, err)
+		}
+		if itemID == "" || itemIDs[itemID] {
+			t.Fatalf("SendData %d returned item ID %q, want a new one", i, itemID)
+		}
+		itemIDs[itemID] = true
+	}
+
+	if err := sdk.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := len(srv.RequestsTo("/data")); got != 5 {
+		t.Errorf("got %d requests after Close, want 5", got)
+	}
+
+	close(results)
+	for result := range results {
+		if result.Err != nil || result.DataID == "" {
+			t.Errorf("got result %+v, want a data ID", result)
+		}
+		if !itemIDs[result.ItemID] {
+			t.Errorf("got result for item %q, want one of the IDs SendData returned", result.ItemID)
+		}
+		delete(itemIDs, result.ItemID)
+	}
+	if len(itemIDs) != 0 {
+		t.Errorf("no result for items %v", itemIDs)
+	}
+
+	if _, err := sdk.SendData(map[string]int{"seq": 5}, nil); !errors.Is(err, pogr.ErrClosed) {
+		t.Errorf("SendData after Close: got %v, want ErrClosed", err)
+	}
+}
+
+func TestAsyncFullResultsDoNotBlock(t *testing.T) {
+	results := make(chan pogr.AsyncResult, 1)
+	_, sdk := newTestClient(t, func(config *pogr.Config) {
+		slowAsync(config)
+		config.AsyncConfig.Results = results
+	})
+	defer sdk.Close(context.Background())
+
+	var itemIDs []string
+	send := func() {
+		itemID, err := sdk.SendData(map[string]int{"seq": len(itemIDs)}, nil)
+		if err != nil {
+			t.Fatalf("SendData: %v", err)
+		}
+		itemIDs = append(itemIDs, itemID)
+	}
+
+	for range 3 {
+		send()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := sdk.Flush(ctx); err != nil {
+		t.Fatalf("Flush with a full Results channel: %v", err)
+	}
+	if result := <-results; result.ItemID != itemIDs[0] || result.Dropped != 0 {
+		t.Errorf("got result for %q with %d dropped, want %q with none", result.ItemID, result.Dropped, itemIDs[0])
+	}
+
+	send()
+	if err := sdk.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if result := <-results; result.ItemID != itemIDs[3] || result.Dropped != 2 {
+		t.Errorf("got result for %q with %d dropped, want %q with 2", result.ItemID, result.Dropped, itemIDs[3])
+	}
+}
+
+func TestAsyncQueueFull(t *testing.T) {
+	_, sdk := newTestClient(t, func(config *pogr.Config) {
+		slowAsync(config)
+		config.AsyncConfig.BufferSize = 2
+	})
+	defer sdk.Close(context.Background())
+
+	var err error
+	for i := 0; i < 10 && err == nil; i++ {
+		_, err = sdk.SendData(map[string]int{"seq": i}, nil)
+	}
+	if !errors.Is(err, pogr.ErrQueueFull) {
+		t.Errorf("got %v, want ErrQueueFull once the buffer is full", err)
+	}
+}
+
+func TestAsyncFlushTimeoutKeepsPayloads(t *testing.T) {
+	results := make(chan pogr.AsyncResult, 10)
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		slowAsync(config)
+		config.AsyncConfig.Results = results
+	})
+	defer sdk.Close(context.Background())
+
+	srv.SetLatency(50 * time.Millisecond)
+	for i := range 10 {
+		if _, err := sdk.SendData(map[string]int{"seq": i}, nil); err != nil {
+			t.Fatalf("SendData %d: %v", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Millisecond)
+	defer cancel()
+	if err := sdk.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Flush with a short deadline: got %v, want DeadlineExceeded", err)
+	}
+
+	if err := sdk.Flush(context.Background()); err != nil {
+		t.Fatalf("second Flush: %v", err)
+	}
+	if got := len(srv.RequestsTo("/data")); got != 10 {
+		t.Errorf("got %d requests, want all 10 delivered", got)
+	}
+	for range 10 {
+		if result := <-results; result.Err != nil {
+			t.Errorf("item %s failed: %v", result.ItemID, result.Err)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package pogr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBatchIncomplete is returned when some items of a batch were not accepted
+var ErrBatchIncomplete = errors.New("some batch items failed")
+
+// batchConcurrency bounds the requests in flight when batch items are sent one by one
+const batchConcurrency = 8
+
+// BatchFormat selects how batch items are packed into a bulk request body.
+//
+// Experimental: see BatchConfig.
+type BatchFormat int
+
+const (
+	BatchJSONArray BatchFormat = iota // A JSON array of items
+	BatchNDJSON                       // One JSON item per line
+)
+
+// BatchConfig holds settings for bulk requests, which Send*Batch only makes when
+// Config.EnableBulkRequests is set. A bulk request POSTs a JSON array or NDJSON body to
+// the single-record endpoint and expects a payload.results array in the answer.
+//
+// Experimental: the intake does not document this format, so it may change or be removed
+// in any release. Only enable it against an intake known to accept it.
+type BatchConfig struct {
+	Format          BatchFormat
+	MaxPayloadBytes int // Requests are split to stay under this size
+	MaxItems        int // Requests are split to stay under this many items
+}
+
+// BatchResult reports the outcome of one batch item
+type BatchResult struct {
+	Index  int // Position of the item in the input slice
+	DataID string
+	Err    error
+}
+
+// DefaultBatchConfig returns default batch settings
+func DefaultBatchConfig() *BatchConfig {
+	return &BatchConfig{
+		Format:          BatchJSONArray,
+		MaxPayloadBytes: 1 << 20,
+		MaxItems:        500,
+	}
+}
+
+// newBatchConfig fills unset batch settings with defaults
+func newBatchConfig(config *BatchConfig) *BatchConfig {
+	settings := DefaultBatchConfig()
+	if config != nil {
+		settings.Format = config.Format
+		if config.MaxPayloadBytes > 0 {
+			settings.MaxPayloadBytes = config.MaxPayloadBytes
+		}
+		if config.MaxItems > 0 {
+			settings.MaxItems = config.MaxItems
+		}
+	}
+	return settings
+}
+
+type batchResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	Payload struct {
+		Results []struct {
+			DataID string `json:"data_id"`
+			Error  string `json:"error,omitempty"`
+		} `json:"results"`
+	} `json:"payload,omitempty"`
+}
+
+// batchItem is a marshaled batch item and its position in the input
+type batchItem struct {
+	index int
+	body  []byte
+}
+
+// SendEventsBatch validates and sends many events, concurrently one per request unless
+// bulk requests are enabled
+func (sdk *pogrSDK) SendEventsBatch(ctx context.Context, events []*Event) ([]BatchResult, error) {
+	return sdk.sendEventsBatch(ctx, nil, events)
+}
+
+// SendLogsBatch sends many log entries, concurrently one per request unless bulk requests are enabled
+func (sdk *pogrSDK) SendLogsBatch(ctx context.Context, logs []LogEntry) ([]BatchResult, error) {
+	return sendBatch(ctx, sdk, nil, "/logs", logs, nil)
+}
+
+// SendMetricsBatch sends many metrics payloads, concurrently one per request unless bulk requests are enabled
+func (sdk *pogrSDK) SendMetricsBatch(ctx context.Context, metrics []MetricsEntry) ([]BatchResult, error) {
+	return sendBatch(ctx, sdk, nil, "/metrics", metrics, nil)
+}
+
+// SendDataBatch sends many data payloads, concurrently one per request unless bulk requests are enabled
+func (sdk *pogrSDK) SendDataBatch(ctx context.Context, data []DataPayload) ([]BatchResult, error) {
+	return sendBatch(ctx, sdk, nil, "/data", data, nil)
+}
+
+// sendEventsBatch validates events before packing them
+func (sdk *pogrSDK) sendEventsBatch(ctx context.Context, session *Session, events []*Event) ([]BatchResult, error) {
+	return sendBatch(ctx, sdk, session, "/event", events, func(event *Event) error {
+		return event.Validate()
+	})
+}
+
+// sendBatch marshals items and reports a result for every item. With bulk requests
+// enabled they are packed into requests within the configured limits; otherwise, and
+// always when the outbox is enabled, each item is delivered on its own with up to
+// batchConcurrency requests in flight, so items sent while offline are queued in the
+// outbox and report ErrQueued. Batches bypass the async pipeline.
+func sendBatch[T any](ctx context.Context, sdk *pogrSDK, session *Session, endpoint string, items []T, validate func(T) error) ([]BatchResult, error) {
+	if sdk.closed.Load() {
+		return nil, ErrClosed
+	}
+
+	config := newBatchConfig(sdk.config.BatchConfig)
+	bulk := sdk.config.EnableBulkRequests && sdk.outbox == nil
+	results := make([]BatchResult, len(items))
+
+	pending := make([]batchItem, 0, len(items))
+	for i, item := range items {
+		results[i].Index = i
+		if validate != nil {
+			if err := validate(item); err != nil {
+				results[i].Err = err
+				continue
+			}
+		}
+
+		body, err := json.Marshal(item)
+		if err != nil {
+			results[i].Err = fmt.Errorf("failed to marshal batch item: %w", err)
+			continue
+		}
+		if bulk && len(body)+2 > config.MaxPayloadBytes {
+			results[i].Err = fmt.Errorf("%w: item is %d bytes, limit is %d", ErrInvalidData, len(body), config.MaxPayloadBytes)
+			continue
+		}
+		pending = append(pending, batchItem{index: i, body: body})
+	}
+
+	if bulk {
+		for _, chunk := range splitBatch(pending, config) {
+			sdk.sendChunk(ctx, session, endpoint, config.Format, chunk, results)
+		}
+	} else {
+		sdk.deliverEach(ctx, session, endpoint, pending, results)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%w: %d of %d", ErrBatchIncomplete, failed, len(items))
+	}
+	return results, nil
+}
+
+// deliverEach sends every item in its own request, a bounded number at a time
+func (sdk *pogrSDK) deliverEach(ctx context.Context, session *Session, endpoint string, items []batchItem, results []BatchResult) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, batchConcurrency)
+	for _, item := range items {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			results[item.index].DataID, results[item.index].Err = sdk.deliver(ctx, session, endpoint, item.body)
+		}()
+	}
+	wg.Wait()
+}
+
+// splitBatch groups items into chunks that fit the payload size and item limits
+func splitBatch(items []batchItem, config *BatchConfig) [][]batchItem {
+	var chunks [][]batchItem
+	var chunk []batchItem
+	size := 2 // Array brackets; NDJSON needs no more than this
+
+	for _, item := range items {
+		if len(chunk) > 0 && (size+len(item.body)+1 > config.MaxPayloadBytes || len(chunk) >= config.MaxItems) {
+			chunks = append(chunks, chunk)
+			chunk, size = nil, 2
+		}
+		chunk = append(chunk, item)
+		size += len(item.body) + 1 // Separator
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// sendChunk sends one packed request and fills in the results of its items
+func (sdk *pogrSDK) sendChunk(ctx context.Context, session *Session, endpoint string, format BatchFormat, chunk []batchItem, results []BatchResult) {
+	fail := func(err error) {
+		for _, item := range chunk {
+			results[item.index].Err = err
+		}
+	}
+
+	body, contentType := packBatch(format, chunk)
+	resp, err := sdk.postAs(ctx, session, endpoint, contentType, body)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	var batchResp batchResponse
+	if err := decodeResponse(endpoint, resp, &batchResp); err != nil {
+		fail(err)
+		return
+	}
+	if !batchResp.Success {
+		fail(newAPIError(endpoint, resp, batchResp.Error))
+		return
+	}
+
+	for i, item := range chunk {
+		if i >= len(batchResp.Payload.Results) {
+			results[item.index].Err = fmt.Errorf("no result for batch item %d from %s", item.index, endpoint)
+			continue
+		}
+		result := batchResp.Payload.Results[i]
+		if result.Error != "" {
+			results[item.index].Err = newAPIError(endpoint, resp, result.Error)
+			continue
+		}
+		results[item.index].DataID = result.DataID
+	}
+}
+
+// packBatch joins marshaled items into a request body and returns its content type
+func packBatch(format BatchFormat, chunk []batchItem) ([]byte, string) {
+	var buf bytes.Buffer
+
+	if format == BatchNDJSON {
+		for _, item := range chunk {
+			buf.Write(item.body)
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), "application/x-ndjson"
+	}
+
+	buf.WriteByte('[')
+	for i, item := range chunk {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(item.body)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), "application/json"
+}
+
+// SendEventsBatch validates and sends many events on this session
+func (s *Session) SendEventsBatch(ctx context.Context, events []*Event) ([]BatchResult, error) {
+	if err := s.use(); err != nil {
+		return nil, err
+	}
+	return s.sdk.sendEventsBatch(ctx, s, events)
+}
+
+// SendLogsBatch sends many log entries on this session
+func (s *Session) SendLogsBatch(ctx context.Context, logs []LogEntry) ([]BatchResult, error) {
+	if err := s.use(); err != nil {
+		return nil, err
+	}
+	return sendBatch(ctx, s.sdk, s, "/logs", logs, nil)
+}
+
+// SendMetricsBatch sends many metrics payloads on this session
+func (s *Session) SendMetricsBatch(ctx context.Context, metrics []MetricsEntry) ([]BatchResult, error) {
+	if err := s.use(); err != nil {
+		return nil, err
+	}
+	return sendBatch(ctx, s.sdk, s, "/metrics", metrics, nil)
+}
+
+// SendDataBatch sends many data payloads on this session
+func (s *Session) SendDataBatch(ctx context.Context, data []DataPayload) ([]BatchResult, error) {
+	if err := s.use(); err != nil {
+		return nil, err
+	}
+	return sendBatch(ctx, s.sdk, s, "/data", data, nil)
+}
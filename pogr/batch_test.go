@@ -0,0 +1,233 @@
+package pogr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+func TestSendEventsBatchSplitsByItems(t *testing.T) {
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.EnableBulkRequests = true
+		config.BatchConfig = &pogr.BatchConfig{MaxItems: 2}
+	})
+
+	events := make([]*pogr.Event, 5)
+	for i := range events {
+		events[i] = &pogr.Event{Event: "match", EventType: "kill", EventKey: fmt.Sprintf("kill-%d", i)}
+	}
+	results, err := sdk.SendEventsBatch(context.Background(), events)
+	if err != nil {
+		t.Fatalf("SendEventsBatch: %v", err)
+	}
+
+	requests := srv.RequestsTo("/event")
+	if len(requests) != 3 {
+		t.Fatalf("got %d /event requests, want 3", len(requests))
+	}
+	if got := requests[2].Items[0]["event_key"]; got != "kill-4" {
+		t.Errorf("last request starts with %v, want kill-4", got)
+	}
+	for i, result := range results {
+		if result.Index != i || result.DataID == "" || result.Err != nil {
+			t.Errorf("result %d = %+v, want a data ID", i, result)
+		}
+	}
+}
+
+func TestSendLogsBatchSplitsBySize(t *testing.T) {
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.EnableBulkRequests = true
+		config.BatchConfig = &pogr.BatchConfig{Format: pogr.BatchNDJSON, MaxPayloadBytes: 400}
+	})
+
+	logs := make([]pogr.LogEntry, 6)
+	for i := range logs {
+		logs[i] = pogr.LogEntry{Service: "game", Severity: "info", Log: strings.Repeat("x", 100)}
+	}
+	if _, err := sdk.SendLogsBatch(context.Background(), logs); err != nil {
+		t.Fatalf("SendLogsBatch: %v", err)
+	}
+
+	requests := srv.RequestsTo("/logs")
+	if len(requests) < 2 {
+		t.Fatalf("got %d /logs requests, want the batch split", len(requests))
+	}
+	items := 0
+	for _, req := range requests {
+		if len(req.Body) > 400 {
+			t.Errorf("request body is %d bytes, limit is 400", len(req.Body))
+		}
+		if got := req.Headers.Get("Content-Type"); got != "application/x-ndjson" {
+			t.Errorf("Content-Type = %q, want application/x-ndjson", got)
+		}
+		items += len(req.Items)
+	}
+	if items != len(logs) {
+		t.Errorf("intake received %d items, want %d", items, len(logs))
+	}
+}
+
+func TestSendEventsBatchReportsInvalidItems(t *testing.T) {
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.EnableBulkRequests = true
+		config.BatchConfig = &pogr.BatchConfig{MaxPayloadBytes: 200}
+	})
+
+	events := []*pogr.Event{
+		{Event: "match", EventType: "kill"},
+		{Event: "match"},
+		{Event: "match", EventType: "chat", EventData: map[string]interface{}{"text": strings.Repeat("x", 300)}},
+	}
+	results, err := sdk.SendEventsBatch(context.Background(), events)
+	if !errors.Is(err, pogr.ErrBatchIncomplete) {
+		t.Fatalf("got %v, want ErrBatchIncomplete", err)
+	}
+
+	if results[0].Err != nil || results[0].DataID == "" {
+		t.Errorf("result 0 = %+v, want a data ID", results[0])
+	}
+	if !errors.Is(results[1].Err, pogr.ErrInvalidData) {
+		t.Errorf("result 1 error = %v, want ErrInvalidData for a missing type", results[1].Err)
+	}
+	if !errors.Is(results[2].Err, pogr.ErrInvalidData) {
+		t.Errorf("result 2 error = %v, want ErrInvalidData for an oversized item", results[2].Err)
+	}
+	if got := len(srv.RequestsTo("/event")); got != 1 {
+		t.Errorf("got %d /event requests, want only the valid item sent", got)
+	}
+}
+
+func TestSendDataBatchReportsPartialFailure(t *testing.T) {
+	config := pogrtest.NewServer().Config()
+	config.EnableBulkRequests = true
+	config.HTTPClient = &stubClient{
+		StatusCode: http.StatusOK,
+		Body:       []byte(`{"success":true,"payload":{"results":[{"data_id":"d1"},{"error":"bad score"}]}}`),
+	}
+	sdk := pogr.NewPOGRSDK(config)
+
+	results, err := sdk.SendDataBatch(context.Background(), []pogr.DataPayload{{Data: 1}, {Data: -1}, {Data: 2}})
+	if !errors.Is(err, pogr.ErrBatchIncomplete) {
+		t.Fatalf("got %v, want ErrBatchIncomplete", err)
+	}
+
+	if results[0].DataID != "d1" || results[0].Err != nil {
+		t.Errorf("result 0 = %+v, want data ID d1", results[0])
+	}
+	var apiErr *pogr.APIError
+	if !errors.As(results[1].Err, &apiErr) || apiErr.Message != "bad score" {
+		t.Errorf("result 1 error = %v, want the intake's per-item error", results[1].Err)
+	}
+	if results[2].Err == nil {
+		t.Error("result 2 has no error although the intake returned no result for it")
+	}
+}
+
+func TestSendDataBatchWithoutBulkSendsItemsSeparately(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	results, err := sdk.SendDataBatch(context.Background(), []pogr.DataPayload{{Data: 1}, {Data: 2}, {Data: 3}})
+	if err != nil {
+		t.Fatalf("SendDataBatch: %v", err)
+	}
+
+	requests := srv.RequestsTo("/data")
+	if len(requests) != 3 {
+		t.Fatalf("got %d /data requests, want one per item", len(requests))
+	}
+	sent := make(map[float64]bool)
+	for i, req := range requests {
+		if req.Items != nil {
+			t.Errorf("request %d = %s, want a single record", i, req.Body)
+		}
+		sent[req.Payload["data"].(float64)] = true
+	}
+	for i, result := range results {
+		if !sent[float64(i+1)] {
+			t.Errorf("item %d was not sent", i)
+		}
+		if result.DataID == "" || result.Err != nil {
+			t.Errorf("result %d = %+v, want a data ID", i, result)
+		}
+	}
+}
+
+func TestSendDataBatchWithoutBulkSendsConcurrently(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	srv.SetLatency(100 * time.Millisecond)
+	data := make([]pogr.DataPayload, 8)
+	for i := range data {
+		data[i] = pogr.DataPayload{Data: i}
+	}
+
+	start := time.Now()
+	if _, err := sdk.SendDataBatch(context.Background(), data); err != nil {
+		t.Fatalf("SendDataBatch: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("SendDataBatch took %v, want the requests sent concurrently", elapsed)
+	}
+	if got := len(srv.RequestsTo("/data")); got != len(data) {
+		t.Errorf("got %d /data requests, want %d", got, len(data))
+	}
+}
+
+func TestSendDataBatchQueuesInOutbox(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	config := srv.Config()
+	config.EnableBulkRequests = true
+	config.EnableOutbox = true
+	config.OutboxConfig = &pogr.OutboxConfig{Dir: t.TempDir(), ReplayInterval: time.Hour}
+	sdk := pogr.NewPOGRSDK(config)
+	defer sdk.Close(context.Background())
+
+	srv.FailNext("/data", http.StatusServiceUnavailable, "down")
+	results, err := sdk.SendDataBatch(context.Background(), []pogr.DataPayload{{Data: map[string]int{"seq": 1}}, {Data: map[string]int{"seq": 2}}})
+	if !errors.Is(err, pogr.ErrBatchIncomplete) {
+		t.Fatalf("SendDataBatch during outage: got %v, want ErrBatchIncomplete", err)
+	}
+	queued := 0
+	for _, result := range results {
+		if errors.Is(result.Err, pogr.ErrQueued) {
+			queued++
+		} else if result.Err != nil {
+			t.Errorf("result %d error = %v, want ErrQueued or success", result.Index, result.Err)
+		}
+	}
+	if queued == 0 {
+		t.Error("no batch item reported ErrQueued during the outage")
+	}
+
+	if _, err := sdk.SendData(map[string]int{"seq": 3}, nil); err != nil {
+		t.Fatalf("SendData after outage: %v", err)
+	}
+
+	// Batch items are sent concurrently, so only the later send has a fixed position
+	delivered := make(map[float64]int)
+	ok := 0
+	for i, req := range srv.RequestsTo("/data") {
+		if req.Status == http.StatusOK {
+			data, _ := req.Payload["data"].(map[string]interface{})
+			seq, _ := data["seq"].(float64)
+			delivered[seq] = i
+			ok++
+		}
+	}
+	if len(delivered) != 3 || ok != 3 {
+		t.Fatalf("delivered %v, want seq 1, 2 and 3 once each", delivered)
+	}
+	if delivered[3] < delivered[1] || delivered[3] < delivered[2] {
+		t.Errorf("seq 3 delivered before the queued batch items: %v", delivered)
+	}
+}
@@ -0,0 +1,98 @@
+package pogr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"sync"
+)
+
+// Compressor encodes request bodies. Implementations must be safe for concurrent use;
+// zstd or other encodings can be plugged in by implementing this interface.
+type Compressor interface {
+	Encoding() string // Value of the Content-Encoding header, e.g. "gzip"
+	Compress(data []byte) ([]byte, error)
+}
+
+// CompressionConfig holds settings for request body compression
+type CompressionConfig struct {
+	Compressor Compressor // Defaults to gzip at the default level
+	MinSize    int        // Bodies smaller than this many bytes are sent uncompressed
+}
+
+// DefaultCompressionConfig returns default compression settings
+func DefaultCompressionConfig() *CompressionConfig {
+	return &CompressionConfig{
+		Compressor: NewGzipCompressor(gzip.DefaultCompression),
+		MinSize:    1024,
+	}
+}
+
+// newCompressionConfig fills unset compression settings with defaults
+func newCompressionConfig(config *CompressionConfig) *CompressionConfig {
+	settings := DefaultCompressionConfig()
+	if config != nil {
+		if config.Compressor != nil {
+			settings.Compressor = config.Compressor
+		}
+		if config.MinSize > 0 {
+			settings.MinSize = config.MinSize
+		}
+	}
+	return settings
+}
+
+// gzipCompressor compresses with gzip, reusing writers between requests
+type gzipCompressor struct {
+	level   int
+	writers sync.Pool
+}
+
+// NewGzipCompressor returns a gzip Compressor using a compress/gzip level
+func NewGzipCompressor(level int) Compressor {
+	return &gzipCompressor{level: level}
+}
+
+func (c *gzipCompressor) Encoding() string {
+	return "gzip"
+}
+
+func (c *gzipCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w, ok := c.writers.Get().(*gzip.Writer)
+	if ok {
+		w.Reset(&buf)
+	} else {
+		var err error
+		if w, err = gzip.NewWriterLevel(&buf, c.level); err != nil {
+			return nil, fmt.Errorf("failed to create gzip writer: %w", err)
+		}
+	}
+	defer c.writers.Put(w)
+
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// compressBody compresses a request body when it is large enough and compression pays off.
+// It returns the body to send and its Content-Encoding, empty when sent as is.
+func compressBody(config *CompressionConfig, body []byte) ([]byte, string, error) {
+	if config == nil || len(body) == 0 || len(body) < config.MinSize {
+		return body, "", nil
+	}
+
+	compressed, err := config.Compressor.Compress(body)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(compressed) >= len(body) {
+		return body, "", nil
+	}
+	return compressed, config.Compressor.Encoding(), nil
+}
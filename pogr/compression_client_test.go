@@ -0,0 +1,124 @@
+package pogr_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+// inflatingCompressor makes every body larger, like incompressible data does
+type inflatingCompressor struct{}
+
+func (inflatingCompressor) Encoding() string { return "gzip" }
+
+func (inflatingCompressor) Compress(data []byte) ([]byte, error) {
+	return append(bytes.Clone(data), make([]byte, 64)...), nil
+}
+
+// countingCompressor gzips bodies and counts how many it was asked to compress
+type countingCompressor struct {
+	pogr.Compressor
+	calls atomic.Int32
+}
+
+func (c *countingCompressor) Compress(data []byte) ([]byte, error) {
+	c.calls.Add(1)
+	return c.Compressor.Compress(data)
+}
+
+// compressingClient enables compression with the given settings
+func compressingClient(config *pogr.CompressionConfig) func(*pogr.Config) {
+	return func(c *pogr.Config) {
+		c.EnableCompression = true
+		c.CompressionConfig = config
+	}
+}
+
+func TestCompressionAboveMinSize(t *testing.T) {
+	srv, sdk := newTestClient(t, compressingClient(&pogr.CompressionConfig{MinSize: 256}))
+
+	if _, err := sdk.SendData(map[string]string{"text": strings.Repeat("abc", 200)}, nil); err != nil {
+		t.Fatalf("SendData large: %v", err)
+	}
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); err != nil {
+		t.Fatalf("SendData small: %v", err)
+	}
+
+	requests := srv.RequestsTo("/data")
+	if len(requests) != 2 {
+		t.Fatalf("got %d /data requests, want 2", len(requests))
+	}
+	if got := requests[0].Headers.Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("large body Content-Encoding = %q, want gzip", got)
+	}
+	if data, _ := requests[0].Payload["data"].(map[string]interface{}); len(data["text"].(string)) != 600 {
+		t.Errorf("intake decoded %v, want the original payload", requests[0].Payload)
+	}
+	if got := requests[1].Headers.Get("Content-Encoding"); got != "" {
+		t.Errorf("small body Content-Encoding = %q, want none", got)
+	}
+}
+
+func TestCompressionSkipsBodiesThatGrow(t *testing.T) {
+	srv, sdk := newTestClient(t, compressingClient(&pogr.CompressionConfig{Compressor: inflatingCompressor{}, MinSize: 1}))
+
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); err != nil {
+		t.Fatalf("SendData: %v", err)
+	}
+
+	requests := srv.RequestsTo("/data")
+	if len(requests) != 1 {
+		t.Fatalf("got %d /data requests, want 1", len(requests))
+	}
+	if got := requests[0].Headers.Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want the body sent plain", got)
+	}
+	if requests[0].Payload["data"] == nil {
+		t.Errorf("intake received %q, want the plain payload", requests[0].Body)
+	}
+}
+
+func TestCompressionLeavesEncodedBodies(t *testing.T) {
+	// An interceptor that encodes bodies itself, as a caller with its own encoding would
+	encodeBody := func(next pogr.HTTPClient) pogr.HTTPClient {
+		return pogr.HTTPClientFunc(func(req *pogr.Request) (*pogr.Response, error) {
+			var buf bytes.Buffer
+			w := gzip.NewWriter(&buf)
+			w.Write(req.Body)
+			w.Close()
+
+			clone := *req
+			clone.Body = buf.Bytes()
+			clone.Headers = map[string]string{"content-encoding": "gzip"}
+			for key, value := range req.Headers {
+				clone.Headers[key] = value
+			}
+			return next.Do(&clone)
+		})
+	}
+	compressor := &countingCompressor{Compressor: pogr.NewGzipCompressor(gzip.DefaultCompression)}
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		compressingClient(&pogr.CompressionConfig{Compressor: compressor, MinSize: 1})(config)
+		config.Interceptors = []pogr.Interceptor{encodeBody}
+	})
+
+	if _, err := sdk.SendData(map[string]string{"text": strings.Repeat("abc", 200)}, nil); err != nil {
+		t.Fatalf("SendData: %v", err)
+	}
+
+	requests := srv.RequestsTo("/data")
+	if len(requests) != 1 {
+		t.Fatalf("got %d /data requests, want 1", len(requests))
+	}
+	if got := compressor.calls.Load(); got != 0 {
+		t.Errorf("client compressed %d already encoded bodies, want 0", got)
+	}
+	if !json.Valid(requests[0].Body) {
+		t.Errorf("intake decoded %q, want the JSON body compressed once", requests[0].Body)
+	}
+}
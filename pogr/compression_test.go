@@ -0,0 +1,71 @@
+package pogr
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// benchmarkPayload builds an event batch of roughly size bytes with realistic, only partly repetitive data
+func benchmarkPayload(size int) []byte {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	var events []*Event
+	var body []byte
+	for len(body) < size {
+		events = append(events, &Event{
+			Event:     "match",
+			SubEvent:  "kill",
+			EventType: "combat",
+			EventKey:  fmt.Sprintf("match-%d", rng.IntN(1000)),
+			EventData: map[string]interface{}{
+				"weapon":   []string{"rifle", "shotgun", "sniper", "pistol"}[rng.IntN(4)],
+				"distance": rng.Float64() * 300,
+				"headshot": rng.IntN(2) == 0,
+				"position": []float64{rng.Float64() * 4096, rng.Float64() * 4096, rng.Float64() * 512},
+				"player":   fmt.Sprintf("%016x", rng.Uint64()),
+			},
+		})
+		body, _ = json.Marshal(events)
+	}
+	return body
+}
+
+// BenchmarkCompressBody shows the CPU cost (ns/op, MB/s) of each level against the bandwidth it saves
+func BenchmarkCompressBody(b *testing.B) {
+	levels := []struct {
+		name  string
+		level int
+	}{
+		{"speed", gzip.BestSpeed},
+		{"default", gzip.DefaultCompression},
+		{"best", gzip.BestCompression},
+	}
+
+	for _, size := range []int{1 << 10, 16 << 10, 256 << 10} {
+		payload := benchmarkPayload(size)
+
+		for _, level := range levels {
+			config := &CompressionConfig{Compressor: NewGzipCompressor(level.level), MinSize: 0}
+
+			b.Run(fmt.Sprintf("%dKiB/gzip-%s", size>>10, level.name), func(b *testing.B) {
+				b.SetBytes(int64(len(payload)))
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				var compressed []byte
+				for range b.N {
+					var err error
+					if compressed, _, err = compressBody(config, payload); err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				b.ReportMetric(float64(len(compressed)), "compressed-bytes")
+				b.ReportMetric(float64(len(compressed))/float64(len(payload)), "ratio")
+			})
+		}
+	}
+}
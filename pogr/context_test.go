@@ -0,0 +1,74 @@
+package pogr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+func TestCancelStopsInFlightRequest(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+	srv.SetLatency(time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := sdk.SendDataContext(ctx, map[string]int{"score": 1}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v, want the request abandoned on cancel", elapsed)
+	}
+}
+
+func TestCancelStopsRetryBackoff(t *testing.T) {
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.EnableRetries = true
+		config.RetryPolicy = &pogr.RetryPolicy{
+			MaxAttempts:          3,
+			BaseDelay:            time.Minute,
+			MaxDelay:             time.Minute,
+			RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		}
+	})
+	srv.FailNext("/event", http.StatusServiceUnavailable, "down")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := sdk.SendEventContext(ctx, "match", "kill", "combat", "", "", nil, nil)
+	var apiErr *pogr.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("got %v, want the 503 that started the backoff", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v, want the backoff cut short on cancel", elapsed)
+	}
+	if got := len(srv.RequestsTo("/event")); got != 1 {
+		t.Errorf("got %d attempts, want 1", got)
+	}
+}
+
+func TestCancelledContextSendsNothing(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := sdk.SendLogContext(ctx, "game", "prod", "info", "app", "started", nil, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("SendLogContext: got %v, want context.Canceled", err)
+	}
+	if _, err := sdk.InitWithAssociationIDContext(ctx, "player-1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("InitWithAssociationIDContext: got %v, want context.Canceled", err)
+	}
+	if got := len(srv.Requests()); got != 0 {
+		t.Errorf("got %d requests, want none on a cancelled context", got)
+	}
+}
@@ -0,0 +1,94 @@
+package pogr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxErrorSnippet bounds how much of a non-JSON error body is kept in an APIError
+const maxErrorSnippet = 256
+
+// APIError describes a request the intake rejected or failed to process
+type APIError struct {
+	StatusCode int               // HTTP status code of the response
+	Endpoint   string            // Intake endpoint, e.g. "/data"
+	Message    string            // Error reported by the intake
+	Headers    map[string]string // Response headers
+	RequestID  string            // Server-assigned request ID, if any
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("pogr: %s failed with status %d: %s (request id %s)", e.Endpoint, e.StatusCode, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("pogr: %s failed with status %d: %s", e.Endpoint, e.StatusCode, e.Message)
+}
+
+// Retryable reports whether repeating the request may succeed
+func (e *APIError) Retryable() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return e.StatusCode >= http.StatusInternalServerError
+}
+
+// Is maps status codes onto the package's sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrInvalidData:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
+// newAPIError builds an APIError from a response, tolerating non-JSON bodies
+func newAPIError(endpoint string, resp *Response, message string) *APIError {
+	if message == "" {
+		message = errorMessage(resp)
+	}
+
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Endpoint:   endpoint,
+		Message:    message,
+		Headers:    resp.Headers,
+		RequestID:  headerValue(resp.Headers, "X-Request-Id"),
+	}
+}
+
+// errorMessage extracts a readable message from an error response body
+func errorMessage(resp *Response) string {
+	var body struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(resp.Body, &body) == nil {
+		if body.Error != "" {
+			return body.Error
+		}
+		if body.Message != "" {
+			return body.Message
+		}
+	}
+
+	text := strings.TrimSpace(string(resp.Body))
+	contentType := headerValue(resp.Headers, "Content-Type")
+	if text == "" || strings.HasPrefix(text, "<") || strings.Contains(contentType, "html") {
+		// Proxies and load balancers answer with HTML pages that are useless in an error
+		if status := http.StatusText(resp.StatusCode); status != "" {
+			return status
+		}
+		return "unexpected response"
+	}
+
+	if len(text) > maxErrorSnippet {
+		text = text[:maxErrorSnippet] + "..."
+	}
+	return text
+}
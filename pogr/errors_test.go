@@ -0,0 +1,146 @@
+package pogr_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+// stubClient answers every request with a fixed response
+type stubClient pogr.Response
+
+func (c *stubClient) Do(req *pogr.Request) (*pogr.Response, error) {
+	resp := pogr.Response(*c)
+	return &resp, nil
+}
+
+func TestAPIErrorMapsStatusCodes(t *testing.T) {
+	tests := []struct {
+		status       int
+		unauthorized bool
+		invalid      bool
+		retryable    bool
+	}{
+		{http.StatusBadRequest, false, true, false},
+		{http.StatusUnauthorized, true, false, false},
+		{http.StatusForbidden, true, false, false},
+		{http.StatusNotFound, false, false, false},
+		{http.StatusRequestTimeout, false, false, true},
+		{http.StatusUnprocessableEntity, false, true, false},
+		{http.StatusTooManyRequests, false, false, true},
+		{http.StatusInternalServerError, false, false, true},
+		{http.StatusServiceUnavailable, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			err := error(&pogr.APIError{StatusCode: tt.status, Endpoint: "/data", Message: "rejected"})
+
+			if got := errors.Is(err, pogr.ErrUnauthorized); got != tt.unauthorized {
+				t.Errorf("errors.Is(ErrUnauthorized) = %v, want %v", got, tt.unauthorized)
+			}
+			if got := errors.Is(err, pogr.ErrInvalidData); got != tt.invalid {
+				t.Errorf("errors.Is(ErrInvalidData) = %v, want %v", got, tt.invalid)
+			}
+			if got := err.(*pogr.APIError).Retryable(); got != tt.retryable {
+				t.Errorf("Retryable() = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestAPIErrorFromIntake(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+	srv.FailNext("/logs", http.StatusUnprocessableEntity, "log is required")
+
+	_, err := sdk.SendLog("game", "prod", "info", "app", "", nil, nil)
+	var apiErr *pogr.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got %v, want an APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Endpoint != "/logs" || apiErr.Message != "log is required" {
+		t.Errorf("got %+v, want the intake's 422 and message for /logs", apiErr)
+	}
+	if !errors.Is(err, pogr.ErrInvalidData) {
+		t.Errorf("errors.Is(ErrInvalidData) = false for %v", err)
+	}
+}
+
+func TestAPIErrorMessageFromUnusualBodies(t *testing.T) {
+	long := strings.Repeat("x", 1000)
+
+	tests := []struct {
+		name    string
+		resp    pogr.Response
+		message string
+	}{
+		{
+			name:    "html page",
+			resp:    pogr.Response{StatusCode: http.StatusBadGateway, Body: []byte("<html><body>502 Bad Gateway</body></html>")},
+			message: "Bad Gateway",
+		},
+		{
+			name:    "html content type",
+			resp:    pogr.Response{StatusCode: http.StatusServiceUnavailable, Body: []byte("maintenance"), Headers: map[string]string{"Content-Type": "text/html"}},
+			message: "Service Unavailable",
+		},
+		{
+			name:    "empty body",
+			resp:    pogr.Response{StatusCode: http.StatusInternalServerError},
+			message: "Internal Server Error",
+		},
+		{
+			name:    "json message field",
+			resp:    pogr.Response{StatusCode: http.StatusBadRequest, Body: []byte(`{"message":"bad tags"}`)},
+			message: "bad tags",
+		},
+		{
+			name:    "plain text",
+			resp:    pogr.Response{StatusCode: http.StatusBadRequest, Body: []byte("  bad tags\n")},
+			message: "bad tags",
+		},
+		{
+			name:    "long plain text",
+			resp:    pogr.Response{StatusCode: http.StatusBadRequest, Body: []byte(long)},
+			message: long[:256] + "...",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := pogrtest.NewServer().Config()
+			config.HTTPClient = (*stubClient)(&tt.resp)
+			sdk := pogr.NewPOGRSDK(config)
+
+			_, err := sdk.SendData(map[string]int{"score": 1}, nil)
+			var apiErr *pogr.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("got %v, want an APIError", err)
+			}
+			if apiErr.StatusCode != tt.resp.StatusCode || apiErr.Message != tt.message {
+				t.Errorf("got status %d and message %q, want %d and %q", apiErr.StatusCode, apiErr.Message, tt.resp.StatusCode, tt.message)
+			}
+		})
+	}
+}
+
+func TestAPIErrorRequestID(t *testing.T) {
+	config := pogrtest.NewServer().Config()
+	config.HTTPClient = &stubClient{
+		StatusCode: http.StatusInternalServerError,
+		Body:       []byte(`{"success":false,"error":"boom"}`),
+		Headers:    map[string]string{"X-Request-Id": "req-42"},
+	}
+	sdk := pogr.NewPOGRSDK(config)
+
+	_, err := sdk.SendData(map[string]int{"score": 1}, nil)
+	var apiErr *pogr.APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "req-42" {
+		t.Fatalf("got %v, want an APIError carrying request id req-42", err)
+	}
+	if !strings.Contains(err.Error(), "req-42") {
+		t.Errorf("error %q does not mention the request id", err)
+	}
+}
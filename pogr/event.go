@@ -0,0 +1,91 @@
+package pogr
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Event describes a game event sent to the event endpoint
+type Event struct {
+	Event     string                 `json:"event"`
+	SubEvent  string                 `json:"sub_event"`
+	EventType string                 `json:"event_type"`
+	EventFlag string                 `json:"event_flag"`
+	EventKey  string                 `json:"event_key"`
+	EventData map[string]interface{} `json:"event_data"`
+	Tags      *Tags                  `json:"tags"`
+}
+
+// Validate checks that the required event fields are set
+func (e *Event) Validate() error {
+	if e == nil {
+		return fmt.Errorf("%w: event is nil", ErrInvalidData)
+	}
+	if e.Event == "" {
+		return fmt.Errorf("%w: event name is required", ErrInvalidData)
+	}
+	if e.EventType == "" {
+		return fmt.Errorf("%w: event type is required for event %q", ErrInvalidData, e.Event)
+	}
+	return nil
+}
+
+// EventBuilder assembles an Event field by field
+type EventBuilder struct {
+	event Event
+}
+
+// NewEvent starts building an event with the given name
+func NewEvent(name string) *EventBuilder {
+	return &EventBuilder{event: Event{Event: name}}
+}
+
+// Sub sets the sub-event
+func (b *EventBuilder) Sub(subEvent string) *EventBuilder {
+	b.event.SubEvent = subEvent
+	return b
+}
+
+// Type sets the event type
+func (b *EventBuilder) Type(eventType string) *EventBuilder {
+	b.event.EventType = eventType
+	return b
+}
+
+// Flag sets the event flag
+func (b *EventBuilder) Flag(eventFlag string) *EventBuilder {
+	b.event.EventFlag = eventFlag
+	return b
+}
+
+// Key sets the event key
+func (b *EventBuilder) Key(eventKey string) *EventBuilder {
+	b.event.EventKey = eventKey
+	return b
+}
+
+// Data adds a single entry to the event data
+func (b *EventBuilder) Data(key string, value interface{}) *EventBuilder {
+	if b.event.EventData == nil {
+		b.event.EventData = make(map[string]interface{})
+	}
+	b.event.EventData[key] = value
+	return b
+}
+
+// Tags sets the user tags attached to the event
+func (b *EventBuilder) Tags(tags *Tags) *EventBuilder {
+	b.event.Tags = tags
+	return b
+}
+
+// Build validates and returns the event
+func (b *EventBuilder) Build() (*Event, error) {
+	event := b.event
+	event.EventData = maps.Clone(b.event.EventData)
+
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
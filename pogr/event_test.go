@@ -0,0 +1,94 @@
+package pogr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+func TestEventBuilderRequiresNameAndType(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *pogr.EventBuilder
+	}{
+		{"missing name", pogr.NewEvent("").Type("progression")},
+		{"missing type", pogr.NewEvent("player_login")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); !errors.Is(err, pogr.ErrInvalidData) {
+				t.Errorf("Build: got %v, want ErrInvalidData", err)
+			}
+		})
+	}
+
+	var event *pogr.Event
+	if err := event.Validate(); !errors.Is(err, pogr.ErrInvalidData) {
+		t.Errorf("Validate on a nil event: got %v, want ErrInvalidData", err)
+	}
+}
+
+func TestEventBuilderCopiesData(t *testing.T) {
+	builder := pogr.NewEvent("player_login").Type("session").Data("level", 1)
+	first, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	builder.Data("level", 2)
+	if first.EventData["level"] != 1 {
+		t.Errorf("got level %v after reusing the builder, want 1", first.EventData["level"])
+	}
+}
+
+func TestSendEventObject(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	event, err := pogr.NewEvent("player_login").
+		Sub("level_up").
+		Type("progression").
+		Flag("milestone").
+		Key("player-1").
+		Data("level", 7).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if _, err := sdk.SendEventObject(event); err != nil {
+		t.Fatalf("SendEventObject: %v", err)
+	}
+
+	requests := srv.RequestsTo("/event")
+	if len(requests) != 1 {
+		t.Fatalf("got %d /event requests, want 1", len(requests))
+	}
+	payload := requests[0].Payload
+	want := map[string]string{
+		"event":      "player_login",
+		"sub_event":  "level_up",
+		"event_type": "progression",
+		"event_flag": "milestone",
+		"event_key":  "player-1",
+	}
+	for field, value := range want {
+		if payload[field] != value {
+			t.Errorf("%s = %v, want %q", field, payload[field], value)
+		}
+	}
+	if data, _ := payload["event_data"].(map[string]interface{}); data["level"] != float64(7) {
+		t.Errorf("event_data = %v, want level 7", payload["event_data"])
+	}
+}
+
+func TestSendEventObjectRejectsInvalidEvent(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	_, err := sdk.SendEventObject(&pogr.Event{Event: "player_login"})
+	if !errors.Is(err, pogr.ErrInvalidData) {
+		t.Errorf("got %v, want ErrInvalidData", err)
+	}
+	if got := len(srv.RequestsTo("/event")); got != 0 {
+		t.Errorf("got %d /event requests for an invalid event, want 0", got)
+	}
+}
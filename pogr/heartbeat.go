@@ -0,0 +1,191 @@
+package pogr
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// HeartbeatConfig holds settings for session heartbeats. The intake has no liveness endpoint,
+// so heartbeats and Session.Ping send Event to /event, where it shows up in the game's event data.
+type HeartbeatConfig struct {
+	Interval    time.Duration                     // Time between heartbeats
+	MaxFailures int                               // Consecutive failures before the session is considered lost
+	Event       *Event                            // Sent by each heartbeat and Ping, defaults to session/heartbeat/presence
+	OnLost      func(session *Session, err error) // Optional; called once a lost session has been ended locally
+}
+
+// HeartbeatHealth reports the state of a session's heartbeat
+type HeartbeatHealth struct {
+	Running             bool
+	LastSuccess         time.Time
+	ConsecutiveFailures int
+	LastError           error
+	Lost                bool
+}
+
+// DefaultHeartbeatConfig returns default heartbeat settings
+func DefaultHeartbeatConfig() *HeartbeatConfig {
+	return &HeartbeatConfig{
+		Interval:    30 * time.Second,
+		MaxFailures: 3,
+		Event:       &Event{Event: "session", SubEvent: "heartbeat", EventType: "presence"},
+	}
+}
+
+// newHeartbeatConfig fills unset heartbeat settings with defaults
+func newHeartbeatConfig(config *HeartbeatConfig) *HeartbeatConfig {
+	settings := DefaultHeartbeatConfig()
+	if config != nil {
+		settings.OnLost = config.OnLost
+		if config.Event != nil {
+			settings.Event = config.Event
+		}
+		if config.Interval > 0 {
+			settings.Interval = config.Interval
+		}
+		if config.MaxFailures > 0 {
+			settings.MaxFailures = config.MaxFailures
+		}
+	}
+	return settings
+}
+
+// heartbeat pings a session from a background worker
+type heartbeat struct {
+	session *Session
+	config  *HeartbeatConfig
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	mu     sync.Mutex // Protects health
+	health HeartbeatHealth
+}
+
+// StartHeartbeat pings the session every interval until it ends, is lost or StopHeartbeat is called.
+// A lost session is ended locally, without contacting the intake, so sends on it fail with
+// ErrNoActiveSession. A nil config uses DefaultHeartbeatConfig. A running heartbeat is replaced.
+func (s *Session) StartHeartbeat(config *HeartbeatConfig) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &heartbeat{
+		session: s,
+		config:  newHeartbeatConfig(config),
+		ctx:     ctx,
+		cancel:  cancel,
+		health:  HeartbeatHealth{Running: true},
+	}
+
+	s.mu.Lock()
+	previous := s.heartbeat
+	s.heartbeat = h
+	s.mu.Unlock()
+
+	if previous != nil {
+		previous.cancel()
+	}
+	go h.run()
+}
+
+// StopHeartbeat stops the session's heartbeat, if any
+func (s *Session) StopHeartbeat() {
+	s.mu.RLock()
+	h := s.heartbeat
+	s.mu.RUnlock()
+
+	if h != nil {
+		h.cancel()
+	}
+}
+
+// Health returns the state of the session's heartbeat
+func (s *Session) Health() HeartbeatHealth {
+	s.mu.RLock()
+	h := s.heartbeat
+	s.mu.RUnlock()
+
+	if h == nil {
+		return HeartbeatHealth{}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	health := h.health
+	health.Running = health.Running && h.ctx.Err() == nil
+	return health
+}
+
+// run pings the session every interval until cancelled or the session is lost
+func (h *heartbeat) run() {
+	ticker := time.NewTicker(h.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+			if lost, err := h.beat(); lost {
+				h.lose(err)
+				return
+			}
+		}
+	}
+}
+
+// beat sends one heartbeat, renewing an expired session, and reports whether the session is lost
+func (h *heartbeat) beat() (bool, error) {
+	sessionID := h.session.ID()
+	err := h.session.ping(h.ctx, h.config.Event)
+	if h.ctx.Err() != nil {
+		return false, nil
+	}
+
+	switch {
+	case errors.Is(err, ErrNoActiveSession):
+		// Ended elsewhere, e.g. by End racing this beat; nothing was lost
+		h.cancel()
+		return false, nil
+	case errors.Is(err, ErrUnauthorized):
+		if err = h.session.renew(h.ctx, sessionID); err != nil {
+			h.session.mu.RLock()
+			ending := h.session.ending
+			h.session.mu.RUnlock()
+
+			if ending || h.ctx.Err() != nil {
+				// End expired the session on the intake; it was not lost
+				h.cancel()
+				return false, nil
+			}
+			return true, err
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err != nil {
+		h.health.ConsecutiveFailures++
+		h.health.LastError = err
+		return h.health.ConsecutiveFailures >= h.config.MaxFailures, err
+	}
+
+	h.health.LastSuccess = time.Now()
+	h.health.ConsecutiveFailures = 0
+	h.health.LastError = nil
+	return false, nil
+}
+
+// lose records the session as lost, ends it locally and fires the hook
+func (h *heartbeat) lose(err error) {
+	h.mu.Lock()
+	h.health.Running = false
+	h.health.Lost = true
+	h.health.LastError = err
+	h.mu.Unlock()
+
+	h.session.discard()
+	if h.config.OnLost != nil {
+		h.config.OnLost(h.session, err)
+	}
+}
@@ -0,0 +1,174 @@
+package pogr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+// waitFor polls cond until it holds or a second passes
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// fastHeartbeat beats every 10ms and declares a session lost after two failures
+func fastHeartbeat(onLost func(*pogr.Session, error)) func(*pogr.Config) {
+	return func(config *pogr.Config) {
+		config.EnableHeartbeat = true
+		config.HeartbeatConfig = &pogr.HeartbeatConfig{
+			Interval:    10 * time.Millisecond,
+			MaxFailures: 2,
+			OnLost:      onLost,
+		}
+	}
+}
+
+func TestHeartbeatKeepsHealthySession(t *testing.T) {
+	srv, sdk := newTestClient(t, fastHeartbeat(nil))
+	defer sdk.Close(context.Background())
+
+	session, err := sdk.InitWithUserJWT("token")
+	if err != nil {
+		t.Fatalf("InitWithUserJWT: %v", err)
+	}
+
+	waitFor(t, "two accepted heartbeats", func() bool { return len(srv.RequestsTo("/event")) >= 2 })
+
+	health := session.Health()
+	if !health.Running || health.LastSuccess.IsZero() || health.ConsecutiveFailures != 0 || health.Lost {
+		t.Errorf("got health %+v, want a running heartbeat with a recent success", health)
+	}
+	ping := srv.RequestsTo("/event")[0]
+	if ping.Status != http.StatusOK || ping.SessionID != session.ID() || ping.Payload["sub_event"] != "heartbeat" {
+		t.Errorf("got ping %+v, want an accepted heartbeat event on the session", ping.Payload)
+	}
+}
+
+func TestHeartbeatLosesSessionAfterFailures(t *testing.T) {
+	lost := make(chan error, 1)
+	srv, sdk := newTestClient(t, fastHeartbeat(func(session *pogr.Session, err error) {
+		if session.Active() {
+			t.Error("OnLost called while the session is still active")
+		}
+		lost <- err
+	}))
+	defer sdk.Close(context.Background())
+
+	srv.FailNext("/event", http.StatusServiceUnavailable, "down")
+	srv.FailNext("/event", http.StatusServiceUnavailable, "down")
+
+	session, err := sdk.InitWithUserJWT("token")
+	if err != nil {
+		t.Fatalf("InitWithUserJWT: %v", err)
+	}
+
+	select {
+	case err := <-lost:
+		var apiErr *pogr.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("OnLost got %v, want the 503", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnLost not called")
+	}
+
+	if health := session.Health(); !health.Lost || health.Running || health.ConsecutiveFailures != 2 {
+		t.Errorf("got health %+v, want a stopped, lost heartbeat after 2 failures", health)
+	}
+	if _, err := session.SendData(map[string]int{"score": 1}, nil); !errors.Is(err, pogr.ErrNoActiveSession) {
+		t.Errorf("SendData on a lost session: got %v, want ErrNoActiveSession", err)
+	}
+}
+
+func TestHeartbeatRenewsExpiredSession(t *testing.T) {
+	srv, sdk := newTestClient(t, fastHeartbeat(nil))
+	defer sdk.Close(context.Background())
+
+	session, err := sdk.InitWithUserJWT("token")
+	if err != nil {
+		t.Fatalf("InitWithUserJWT: %v", err)
+	}
+	expired := session.ID()
+	srv.ExpireSession(expired)
+
+	waitFor(t, "renewal", func() bool { return session.ID() != expired })
+	if !session.Active() || session.Health().Lost {
+		t.Errorf("session active %v, health %+v; want the renewed session healthy", session.Active(), session.Health())
+	}
+}
+
+func TestHeartbeatStopsOnEnd(t *testing.T) {
+	srv, sdk := newTestClient(t, fastHeartbeat(func(*pogr.Session, error) {
+		t.Error("OnLost called for a session ended on purpose")
+	}))
+	defer sdk.Close(context.Background())
+
+	session, err := sdk.InitWithUserJWT("token")
+	if err != nil {
+		t.Fatalf("InitWithUserJWT: %v", err)
+	}
+	waitFor(t, "a heartbeat", func() bool { return len(srv.RequestsTo("/event")) >= 1 })
+
+	if err := session.End(context.Background()); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	beats := len(srv.RequestsTo("/event"))
+	time.Sleep(50 * time.Millisecond)
+
+	if got := len(srv.RequestsTo("/event")); got != beats {
+		t.Errorf("got %d heartbeats after End, want none", got-beats)
+	}
+	if session.Health().Running {
+		t.Error("heartbeat still running after End")
+	}
+}
+
+func TestHeartbeatSendsConfiguredEvent(t *testing.T) {
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		fastHeartbeat(nil)(config)
+		config.HeartbeatConfig.Event = &pogr.Event{Event: "internal", SubEvent: "keepalive", EventType: "sdk"}
+	})
+	defer sdk.Close(context.Background())
+
+	if _, err := sdk.InitWithUserJWT("token"); err != nil {
+		t.Fatalf("InitWithUserJWT: %v", err)
+	}
+	waitFor(t, "a heartbeat", func() bool { return len(srv.RequestsTo("/event")) >= 1 })
+
+	ping := srv.RequestsTo("/event")[0]
+	if ping.Payload["event"] != "internal" || ping.Payload["sub_event"] != "keepalive" {
+		t.Errorf("got ping %+v, want the configured keepalive event", ping.Payload)
+	}
+}
+
+func TestPingSendsConfiguredEvent(t *testing.T) {
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.HeartbeatConfig = &pogr.HeartbeatConfig{Event: &pogr.Event{Event: "internal", SubEvent: "keepalive", EventType: "sdk"}}
+	})
+	defer sdk.Close(context.Background())
+
+	session, err := sdk.InitWithUserJWT("token")
+	if err != nil {
+		t.Fatalf("InitWithUserJWT: %v", err)
+	}
+	if err := session.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+
+	requests := srv.RequestsTo("/event")
+	if len(requests) != 1 || requests[0].Payload["event"] != "internal" {
+		t.Errorf("got %d /event requests, want one with the configured event", len(requests))
+	}
+}
@@ -0,0 +1,108 @@
+package pogr
+
+import (
+	"log"
+	"maps"
+	"strings"
+	"time"
+)
+
+// Version is the SDK version reported by UserAgentInterceptor
+const Version = "0.1.0"
+
+// Interceptor wraps an HTTPClient to observe or modify the requests the SDK issues
+type Interceptor func(next HTTPClient) HTTPClient
+
+// HTTPClientFunc adapts an ordinary function to the HTTPClient interface
+type HTTPClientFunc func(req *Request) (*Response, error)
+
+// Do calls f(req)
+func (f HTTPClientFunc) Do(req *Request) (*Response, error) {
+	return f(req)
+}
+
+// chainInterceptors wraps a client so the first interceptor is the outermost
+func chainInterceptors(client HTTPClient, interceptors []Interceptor) HTTPClient {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		if interceptors[i] != nil {
+			client = interceptors[i](client)
+		}
+	}
+	return client
+}
+
+// withHeaders returns a shallow copy of the request with additional headers,
+// leaving the original untouched so retries start from the same request
+func withHeaders(req *Request, headers map[string]string) *Request {
+	clone := *req
+	clone.Headers = maps.Clone(req.Headers)
+	if clone.Headers == nil {
+		clone.Headers = make(map[string]string, len(headers))
+	}
+	maps.Copy(clone.Headers, headers)
+	return &clone
+}
+
+// LoggingInterceptor logs the method, URL, outcome and duration of every request
+func LoggingInterceptor(logger *log.Logger) Interceptor {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return func(next HTTPClient) HTTPClient {
+		return HTTPClientFunc(func(req *Request) (*Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+			elapsed := time.Since(start)
+
+			if err != nil {
+				logger.Printf("pogr: %s %s failed after %v: %v", req.Method, redactURL(req.URL), elapsed, err)
+			} else {
+				logger.Printf("pogr: %s %s -> %d in %v", req.Method, redactURL(req.URL), resp.StatusCode, elapsed)
+			}
+			return resp, err
+		})
+	}
+}
+
+// TimingInterceptor reports the outcome and duration of every request to observe
+func TimingInterceptor(observe func(req *Request, resp *Response, err error, elapsed time.Duration)) Interceptor {
+	return func(next HTTPClient) HTTPClient {
+		return HTTPClientFunc(func(req *Request) (*Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+			observe(req, resp, err, time.Since(start))
+			return resp, err
+		})
+	}
+}
+
+// HeaderInterceptor adds fixed headers to every request
+func HeaderInterceptor(headers map[string]string) Interceptor {
+	headers = maps.Clone(headers)
+
+	return func(next HTTPClient) HTTPClient {
+		return HTTPClientFunc(func(req *Request) (*Response, error) {
+			return next.Do(withHeaders(req, headers))
+		})
+	}
+}
+
+// UserAgentInterceptor stamps requests with a User-Agent naming the SDK version,
+// prefixed by product (e.g. "my-game/1.2") when it is not empty
+func UserAgentInterceptor(product string) Interceptor {
+	userAgent := "pogr-go-sdk/" + Version
+	if product != "" {
+		userAgent = product + " " + userAgent
+	}
+
+	return HeaderInterceptor(map[string]string{"User-Agent": userAgent})
+}
+
+// redactURL strips query parameters, which may carry Steam tickets, from logged URLs
+func redactURL(rawURL string) string {
+	if i := strings.IndexByte(rawURL, '?'); i >= 0 {
+		return rawURL[:i]
+	}
+	return rawURL
+}
@@ -0,0 +1,124 @@
+package pogr_test
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+func TestInterceptorsRunFirstOutermost(t *testing.T) {
+	var order []string
+	trace := func(name string) pogr.Interceptor {
+		return func(next pogr.HTTPClient) pogr.HTTPClient {
+			return pogr.HTTPClientFunc(func(req *pogr.Request) (*pogr.Response, error) {
+				order = append(order, name+" in")
+				resp, err := next.Do(req)
+				order = append(order, name+" out")
+				return resp, err
+			})
+		}
+	}
+	_, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.Interceptors = []pogr.Interceptor{trace("outer"), nil, trace("inner")}
+	})
+
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); err != nil {
+		t.Fatalf("SendData: %v", err)
+	}
+
+	want := "outer in, inner in, inner out, outer out"
+	if got := strings.Join(order, ", "); got != want {
+		t.Errorf("got order %q, want %q", got, want)
+	}
+}
+
+func TestInterceptorsRunOnEveryRetry(t *testing.T) {
+	var mu sync.Mutex
+	var statuses []int
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		fastRetries(config)
+		config.Interceptors = []pogr.Interceptor{
+			pogr.TimingInterceptor(func(req *pogr.Request, resp *pogr.Response, err error, elapsed time.Duration) {
+				mu.Lock()
+				defer mu.Unlock()
+				if err == nil {
+					statuses = append(statuses, resp.StatusCode)
+				}
+			}),
+		}
+	})
+	srv.FailNext("/data", http.StatusServiceUnavailable, "down")
+
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); err != nil {
+		t.Fatalf("SendData: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(statuses) != 2 || statuses[0] != http.StatusServiceUnavailable || statuses[1] != http.StatusOK {
+		t.Errorf("observed statuses %v, want [503 200]", statuses)
+	}
+}
+
+func TestHeaderAndUserAgentInterceptors(t *testing.T) {
+	var original map[string]string
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.Interceptors = []pogr.Interceptor{
+			func(next pogr.HTTPClient) pogr.HTTPClient {
+				return pogr.HTTPClientFunc(func(req *pogr.Request) (*pogr.Response, error) {
+					resp, err := next.Do(req)
+					original = req.Headers
+					return resp, err
+				})
+			},
+			pogr.HeaderInterceptor(map[string]string{"X-Shard": "eu-1"}),
+			pogr.UserAgentInterceptor("my-game/1.2"),
+		}
+	})
+
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); err != nil {
+		t.Fatalf("SendData: %v", err)
+	}
+
+	requests := srv.RequestsTo("/data")
+	if len(requests) != 1 {
+		t.Fatalf("got %d /data requests, want 1", len(requests))
+	}
+	headers := requests[0].Headers
+	if got := headers.Get("X-Shard"); got != "eu-1" {
+		t.Errorf("X-Shard = %q, want eu-1", got)
+	}
+	if got, want := headers.Get("User-Agent"), "my-game/1.2 pogr-go-sdk/"+pogr.Version; got != want {
+		t.Errorf("User-Agent = %q, want %q", got, want)
+	}
+	if _, ok := original["X-Shard"]; ok {
+		t.Error("HeaderInterceptor modified the caller's request headers")
+	}
+}
+
+func TestLoggingInterceptorRedactsQuery(t *testing.T) {
+	var buf bytes.Buffer
+	interceptor := pogr.LoggingInterceptor(log.New(&buf, "", 0))
+	client := interceptor(pogr.HTTPClientFunc(func(req *pogr.Request) (*pogr.Response, error) {
+		return &pogr.Response{StatusCode: http.StatusOK}, nil
+	}))
+
+	_, err := client.Do(&pogr.Request{Method: http.MethodPost, URL: "https://api.pogr.io/v1/intake/init?steam_ticket=secret"})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	logged := buf.String()
+	if !strings.Contains(logged, "POST") || !strings.Contains(logged, "-> 200") {
+		t.Errorf("log line %q does not show the method and status", logged)
+	}
+	if strings.Contains(logged, "secret") {
+		t.Errorf("log line %q leaks the query string", logged)
+	}
+}
@@ -8,36 +8,71 @@ import (
 // POGRService defines the interface for POGR SDK operations
 type POGRService interface {
 	// Session Management
-	InitWithUserJWT(userJWT string) (string, error)
-	InitWithAssociationID(associationID string) (string, error)
-	InitWithSteamTicket(steamTicket string) (string, error)
-	EndSession() error
+	InitWithUserJWT(userJWT string) (*Session, error)
+	InitWithAssociationID(associationID string) (*Session, error)
+	InitWithSteamTicket(steamTicket string) (*Session, error)
+	InitWithUserJWTContext(ctx context.Context, userJWT string) (*Session, error)
+	InitWithAssociationIDContext(ctx context.Context, associationID string) (*Session, error)
+	InitWithSteamTicketContext(ctx context.Context, steamTicket string) (*Session, error)
+	ResumeSession(ctx context.Context, key string, method InitMethod, credential string) (*Session, error)
 
-	// Data Operations
+	// Data Operations, authenticated with the access or client keys
+	// (use a *Session from Init* to send on a session)
 	SendData(data interface{}, tags *Tags) (string, error)
 	SendEvent(event, subEvent, eventType, eventFlag, eventKey string, eventData map[string]interface{}, tags *Tags) (string, error)
 	SendLog(service, environment, severity, logType, logMessage string, data map[string]interface{}, tags *Tags) (string, error)
 	SendMetrics(service, environment string, metrics map[string]interface{}, tags *Tags) (string, error)
 	SendMonitorData(cpuUsage float64, memoryUsage int, dllsLoaded []string, settings map[string]interface{}) (string, error)
+	SendEventObject(event *Event) (string, error)
+	SendEventObjectContext(ctx context.Context, event *Event) (string, error)
+	SendDataContext(ctx context.Context, data interface{}, tags *Tags) (string, error)
+	SendEventContext(ctx context.Context, event, subEvent, eventType, eventFlag, eventKey string, eventData map[string]interface{}, tags *Tags) (string, error)
+	SendLogContext(ctx context.Context, service, environment, severity, logType, logMessage string, data map[string]interface{}, tags *Tags) (string, error)
+	SendMetricsContext(ctx context.Context, service, environment string, metrics map[string]interface{}, tags *Tags) (string, error)
+	SendMonitorDataContext(ctx context.Context, cpuUsage float64, memoryUsage int, dllsLoaded []string, settings map[string]interface{}) (string, error)
+	SendEventsBatch(ctx context.Context, events []*Event) ([]BatchResult, error)
+	SendLogsBatch(ctx context.Context, logs []LogEntry) ([]BatchResult, error)
+	SendMetricsBatch(ctx context.Context, metrics []MetricsEntry) ([]BatchResult, error)
+	SendDataBatch(ctx context.Context, data []DataPayload) ([]BatchResult, error)
+
+	// Lifecycle
+	Flush(ctx context.Context) error
+	Close(ctx context.Context) error
 
 	// Utility Methods
-	IsInitialized() bool
-	GetSessionID() string
 	ValidateTag(key string) bool
 	PrintConfig() string
+	ConfigSnapshot(revealSecrets bool) ConfigSnapshot
 }
 
 // Config holds the configuration options for the SDK
 type Config struct {
-	ClientKey            string
-	BuildKey             string
-	AccessKey            string
-	SecretKey            string
-	BaseURL              string
-	HTTPClient           HTTPClient
-	Timeout              time.Duration
-	EnableConnectionPool bool
-	PoolConfig           *ConnectionPoolConfig
+	ClientKey             string
+	BuildKey              string
+	AccessKey             string
+	SecretKey             string
+	BaseURL               string
+	HTTPClient            HTTPClient
+	Interceptors          []Interceptor    // Applied to every request, first is outermost
+	OnSessionExpired      SessionRenewFunc // Replaces the default renewal, which repeats the original Init* call
+	SessionStore          SessionStore     // Persists sessions opened with ResumeSession
+	VerifyResumedSessions bool             // Ping a stored session before ResumeSession reuses it; see HeartbeatConfig
+	Timeout               time.Duration
+	EnableConnectionPool  bool
+	PoolConfig            *ConnectionPoolConfig
+	EnableRetries         bool // Also waits out server pauses up to RetryPolicy.MaxDelay; off, paused calls fail fast
+	RetryPolicy           *RetryPolicy
+	RateLimits            map[string]RateLimit // Keyed by endpoint, e.g. "/data"
+	EnableAsync           bool                 // Queue Send* calls; they return a local item ID that AsyncResult.ItemID reports back
+	AsyncConfig           *AsyncConfig
+	EnableOutbox          bool
+	OutboxConfig          *OutboxConfig
+	EnableHeartbeat       bool // Start a heartbeat on every session; see HeartbeatConfig
+	HeartbeatConfig       *HeartbeatConfig
+	EnableCompression     bool // Compress request bodies; applies to the default HTTP client only
+	CompressionConfig     *CompressionConfig
+	EnableBulkRequests    bool         // Experimental: let Send*Batch pack items into one request; see BatchConfig
+	BatchConfig           *BatchConfig // Settings for bulk requests made by the Send*Batch methods
 }
 
 // ConnectionPoolConfig holds connection pool settings
@@ -89,3 +124,22 @@ type DataPayload struct {
 	Data interface{} `json:"data"`
 	Tags *Tags       `json:"tags,omitempty"`
 }
+
+// LogEntry represents a log entry sent to the logs endpoint
+type LogEntry struct {
+	Service     string                 `json:"service"`
+	Environment string                 `json:"environment"`
+	Severity    string                 `json:"severity"`
+	Type        string                 `json:"type"`
+	Log         string                 `json:"log"`
+	Data        map[string]interface{} `json:"data"`
+	Tags        *Tags                  `json:"tags"`
+}
+
+// MetricsEntry represents a metrics payload sent to the metrics endpoint
+type MetricsEntry struct {
+	Service     string                 `json:"service"`
+	Environment string                 `json:"environment"`
+	Metrics     map[string]interface{} `json:"metrics"`
+	Tags        *Tags                  `json:"tags"`
+}
@@ -0,0 +1,238 @@
+package pogr
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigError explains a missing or malformed configuration field
+type ConfigError struct {
+	Field  string // Configuration key, e.g. "TIMEOUT"
+	Source string // Where the value came from, e.g. "POGR_TIMEOUT" or "pogr.conf:4"
+	Value  string // Offending value, empty for missing fields
+	Reason string
+}
+
+// Error implements the error interface
+func (e *ConfigError) Error() string {
+	field := e.Field
+	if e.Value != "" {
+		field += fmt.Sprintf(" %q", e.Value)
+	}
+	if e.Source != "" {
+		field += fmt.Sprintf(" (%s)", e.Source)
+	}
+	return fmt.Sprintf("pogr: invalid %s: %s", field, e.Reason)
+}
+
+// configKeys lists the keys understood by ConfigFromEnv and LoadConfig
+var configKeys = []string{
+	"CLIENT_ID",
+	"BUILD_ID",
+	"ACCESS_KEY",
+	"SECRET_KEY",
+	"BASE_URL",
+	"TIMEOUT",
+	"ENABLE_CONNECTION_POOL",
+	"ENABLE_RETRIES",
+	"ENABLE_ASYNC",
+	"ENABLE_OUTBOX",
+	"OUTBOX_DIR",
+	"ENABLE_HEARTBEAT",
+	"ENABLE_COMPRESSION",
+}
+
+// configFields maps Config field names reported by Validate to their keys
+var configFields = map[string]string{
+	"ClientKey":        "CLIENT_ID",
+	"BuildKey":         "BUILD_ID",
+	"AccessKey":        "ACCESS_KEY",
+	"SecretKey":        "SECRET_KEY",
+	"BaseURL":          "BASE_URL",
+	"Timeout":          "TIMEOUT",
+	"OutboxConfig.Dir": "OUTBOX_DIR",
+}
+
+// configValue is a raw setting and where it came from
+type configValue struct {
+	value  string
+	source string
+}
+
+// ConfigFromEnv builds a Config from environment variables named prefix + "_" + key,
+// e.g. POGR_CLIENT_ID for prefix "POGR". When PREFIX_PROFILE is set (e.g. "prod"),
+// PREFIX_PROD_KEY takes precedence over PREFIX_KEY.
+func ConfigFromEnv(prefix string) (Config, error) {
+	prefix = strings.TrimSuffix(prefix, "_")
+	if prefix != "" {
+		prefix += "_"
+	}
+	profile := strings.ToUpper(os.Getenv(prefix + "PROFILE"))
+
+	values := make(map[string]configValue)
+	for _, key := range configKeys {
+		names := []string{prefix + key}
+		if profile != "" {
+			names = []string{prefix + profile + "_" + key, prefix + key}
+		}
+		for _, name := range names {
+			if value, ok := os.LookupEnv(name); ok {
+				values[key] = configValue{value: value, source: name}
+				break
+			}
+		}
+	}
+
+	return buildConfig(values, func(key string) string { return prefix + key }, nil)
+}
+
+// LoadConfig reads a Config from a file of key=value lines, ignoring profile sections
+func LoadConfig(path string) (Config, error) {
+	return LoadConfigProfile(path, "")
+}
+
+// LoadConfigProfile reads a Config from a file of key=value lines. Keys before the
+// first [section] apply to every profile; keys in [profile] override them.
+//
+//	client_id = abc
+//	timeout = 10s
+//
+//	[prod]
+//	base_url = https://api.pogr.io/v1/intake
+func LoadConfigProfile(path, profile string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	values := make(map[string]configValue)
+	var errs []error
+	section := ""
+	profileFound := profile == ""
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		source := fmt.Sprintf("%s:%d", path, line)
+
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			if strings.EqualFold(section, profile) {
+				profileFound = true
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			errs = append(errs, &ConfigError{Field: text, Source: source, Reason: "expected key=value"})
+			continue
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = unquote(strings.TrimSpace(value))
+
+		if !slices.Contains(configKeys, key) {
+			errs = append(errs, &ConfigError{Field: key, Source: source, Reason: "unknown key"})
+			continue
+		}
+
+		// Shared settings precede every section, so profile values always win
+		if section == "" || strings.EqualFold(section, profile) {
+			values[key] = configValue{value: value, source: source}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Config{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if !profileFound {
+		errs = append(errs, &ConfigError{Field: "profile", Source: path, Value: profile, Reason: "no such profile section"})
+	}
+
+	return buildConfig(values, func(string) string { return path }, errs)
+}
+
+// buildConfig converts raw settings into a Config, collecting every problem.
+// source names where a missing key should be set.
+func buildConfig(values map[string]configValue, source func(key string) string, errs []error) (Config, error) {
+	var config Config
+
+	str := func(key string) string { return values[key].value }
+	boolean := func(key string) bool {
+		raw, ok := values[key]
+		if !ok || raw.value == "" {
+			return false
+		}
+		parsed, err := strconv.ParseBool(raw.value)
+		if err != nil {
+			errs = append(errs, &ConfigError{Field: key, Source: raw.source, Value: raw.value, Reason: "not a boolean"})
+		}
+		return parsed
+	}
+
+	config.ClientKey = str("CLIENT_ID")
+	config.BuildKey = str("BUILD_ID")
+	config.AccessKey = str("ACCESS_KEY")
+	config.SecretKey = str("SECRET_KEY")
+	config.BaseURL = str("BASE_URL")
+	config.EnableConnectionPool = boolean("ENABLE_CONNECTION_POOL")
+	config.EnableRetries = boolean("ENABLE_RETRIES")
+	config.EnableAsync = boolean("ENABLE_ASYNC")
+	config.EnableOutbox = boolean("ENABLE_OUTBOX")
+	config.EnableHeartbeat = boolean("ENABLE_HEARTBEAT")
+	config.EnableCompression = boolean("ENABLE_COMPRESSION")
+
+	if raw, ok := values["TIMEOUT"]; ok && raw.value != "" {
+		timeout, err := time.ParseDuration(raw.value)
+		if err != nil {
+			errs = append(errs, &ConfigError{Field: "TIMEOUT", Source: raw.source, Value: raw.value, Reason: "not a duration such as 30s"})
+		}
+		config.Timeout = timeout
+	}
+
+	if dir := str("OUTBOX_DIR"); dir != "" {
+		config.OutboxConfig = DefaultOutboxConfig()
+		config.OutboxConfig.Dir = dir
+	}
+
+	if err := config.Validate(); err != nil {
+		// Report validation problems by the key and source the caller can fix
+		problems := []error{err}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			problems = joined.Unwrap()
+		}
+		for _, err := range problems {
+			var configErr *ConfigError
+			if errors.As(err, &configErr) {
+				if key, ok := configFields[configErr.Field]; ok {
+					configErr.Field = key
+					configErr.Source = source(key)
+					if raw, ok := values[key]; ok {
+						configErr.Source = raw.source
+					}
+				}
+			}
+			errs = append(errs, err)
+		}
+	}
+	return config, errors.Join(errs...)
+}
+
+// unquote strips matching single or double quotes around a value
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,145 @@
+package pogr_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+// configErrors returns the ConfigErrors joined in err, keyed by field
+func configErrors(t *testing.T, err error) map[string]*pogr.ConfigError {
+	t.Helper()
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("got %v, want joined ConfigErrors", err)
+	}
+	found := make(map[string]*pogr.ConfigError)
+	for _, err := range joined.Unwrap() {
+		var configErr *pogr.ConfigError
+		if !errors.As(err, &configErr) {
+			t.Fatalf("got %v, want a ConfigError", err)
+		}
+		found[configErr.Field] = configErr
+	}
+	return found
+}
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "pogr.conf")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigFromEnvUsesProfile(t *testing.T) {
+	t.Setenv("GAME_PROFILE", "prod")
+	t.Setenv("GAME_CLIENT_ID", "client")
+	t.Setenv("GAME_BUILD_ID", "build")
+	t.Setenv("GAME_BASE_URL", "https://staging.example.com")
+	t.Setenv("GAME_PROD_BASE_URL", "https://prod.example.com")
+	t.Setenv("GAME_TIMEOUT", "15s")
+	t.Setenv("GAME_ENABLE_RETRIES", "true")
+
+	config, err := pogr.ConfigFromEnv("GAME_")
+	if err != nil {
+		t.Fatalf("ConfigFromEnv: %v", err)
+	}
+	if config.BaseURL != "https://prod.example.com" {
+		t.Errorf("BaseURL = %q, want the prod profile's URL", config.BaseURL)
+	}
+	if config.ClientKey != "client" || config.BuildKey != "build" || config.Timeout != 15*time.Second || !config.EnableRetries {
+		t.Errorf("got %+v, want the shared settings applied", config)
+	}
+}
+
+func TestConfigFromEnvNamesEveryProblem(t *testing.T) {
+	t.Setenv("POGR_CLIENT_ID", "client")
+	t.Setenv("POGR_TIMEOUT", "soon")
+	t.Setenv("POGR_ENABLE_ASYNC", "maybe")
+
+	_, err := pogr.ConfigFromEnv("POGR")
+	found := configErrors(t, err)
+
+	if e := found["TIMEOUT"]; e == nil || e.Source != "POGR_TIMEOUT" || e.Value != "soon" {
+		t.Errorf("TIMEOUT error = %+v, want the malformed POGR_TIMEOUT value", e)
+	}
+	if e := found["ENABLE_ASYNC"]; e == nil || e.Source != "POGR_ENABLE_ASYNC" {
+		t.Errorf("ENABLE_ASYNC error = %+v, want the malformed POGR_ENABLE_ASYNC value", e)
+	}
+	if e := found["BUILD_ID"]; e == nil || e.Source != "POGR_BUILD_ID" {
+		t.Errorf("BUILD_ID error = %+v, want the missing POGR_BUILD_ID variable", e)
+	}
+}
+
+func TestLoadConfigProfile(t *testing.T) {
+	path := writeConfigFile(t, `
+# shared settings
+access_key = access
+secret_key = "secret"
+timeout = 5s
+
+[dev]
+base_url = http://localhost:8080
+
+[prod]
+base_url = https://api.pogr.io/v1/intake
+timeout = 30s
+enable_compression = true
+`)
+
+	config, err := pogr.LoadConfigProfile(path, "prod")
+	if err != nil {
+		t.Fatalf("LoadConfigProfile: %v", err)
+	}
+	if config.BaseURL != "https://api.pogr.io/v1/intake" || config.Timeout != 30*time.Second || !config.EnableCompression {
+		t.Errorf("got %+v, want the prod section to override shared settings", config)
+	}
+	if config.AccessKey != "access" || config.SecretKey != "secret" {
+		t.Errorf("got access %q and secret %q, want the shared credentials unquoted", config.AccessKey, config.SecretKey)
+	}
+
+	config, err = pogr.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if config.BaseURL != "" || config.Timeout != 5*time.Second {
+		t.Errorf("got %+v, want only the shared settings", config)
+	}
+}
+
+func TestLoadConfigReportsLines(t *testing.T) {
+	path := writeConfigFile(t, `client_id = client
+build_id = build
+colour = blue
+enable_outbox = true
+not a setting
+`)
+
+	_, err := pogr.LoadConfigProfile(path, "staging")
+	found := configErrors(t, err)
+
+	if e := found["COLOUR"]; e == nil || e.Source != path+":3" {
+		t.Errorf("COLOUR error = %+v, want an unknown key on line 3", e)
+	}
+	if e := found["not a setting"]; e == nil || e.Source != path+":5" {
+		t.Errorf("got %+v, want a malformed line 5", e)
+	}
+	if e := found["profile"]; e == nil || e.Value != "staging" {
+		t.Errorf("profile error = %+v, want the missing staging section", e)
+	}
+	if e := found["OUTBOX_DIR"]; e == nil || e.Source != path {
+		t.Errorf("OUTBOX_DIR error = %+v, want the missing key reported against the file", e)
+	}
+	if !strings.Contains(err.Error(), path+":3") {
+		t.Errorf("error %q does not mention the offending line", err)
+	}
+}
@@ -0,0 +1,229 @@
+package pogr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SessionManagerOptions holds settings for a SessionManager
+type SessionManagerOptions struct {
+	IdleTimeout   time.Duration // Sessions unused for this long are ended, 0 to keep them forever
+	EvictInterval time.Duration // How often idle sessions are looked for; defaults to IdleTimeout/2
+	OnEvictError  func(key string, err error)
+}
+
+// DefaultSessionManagerOptions returns default session manager settings
+func DefaultSessionManagerOptions() *SessionManagerOptions {
+	return &SessionManagerOptions{
+		IdleTimeout: 30 * time.Minute,
+	}
+}
+
+// errSessionExists refuses a new session when another Open already stored one for the key
+var errSessionExists = errors.New("session already open for key")
+
+// SessionManager owns many sessions keyed by player or association ID. Every session
+// shares the client's HTTP client, connection pool, retries and pipelines.
+type SessionManager struct {
+	sdk     *pogrSDK
+	options SessionManagerOptions
+
+	mu       sync.Mutex // Protects sessions and closed
+	sessions map[string]*Session
+	closed   bool
+
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewSessionManager creates a session manager on top of a client created by NewPOGRSDK
+func NewSessionManager(svc POGRService, opts *SessionManagerOptions) (*SessionManager, error) {
+	sdk, ok := svc.(*pogrSDK)
+	if !ok {
+		return nil, errors.New("session manager requires a client created by NewPOGRSDK")
+	}
+
+	options := *DefaultSessionManagerOptions()
+	if opts != nil {
+		options = *opts
+	}
+	if options.EvictInterval <= 0 {
+		options.EvictInterval = options.IdleTimeout / 2
+	}
+
+	m := &SessionManager{
+		sdk:      sdk,
+		options:  options,
+		sessions: make(map[string]*Session),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+
+	if m.options.IdleTimeout > 0 {
+		go m.run()
+	} else {
+		close(m.stopped)
+	}
+	return m, nil
+}
+
+// OpenWithUserJWT starts a JWT session for key, or returns its active session
+func (m *SessionManager) OpenWithUserJWT(ctx context.Context, key string, userJWT string) (*Session, error) {
+	return m.open(ctx, key, InitUserJWT, m.sdk.userJWTInit(userJWT))
+}
+
+// OpenWithAssociationID starts an association ID session for key, or returns its active session
+func (m *SessionManager) OpenWithAssociationID(ctx context.Context, key string, associationID string) (*Session, error) {
+	return m.open(ctx, key, InitAssociationID, m.sdk.associationIDInit(associationID))
+}
+
+// OpenWithSteamTicket starts a Steam ticket session for key, or returns its active session
+func (m *SessionManager) OpenWithSteamTicket(ctx context.Context, key string, steamTicket string) (*Session, error) {
+	return m.open(ctx, key, InitSteamTicket, m.sdk.steamTicketInit(steamTicket))
+}
+
+// open starts a session for key unless one is already active
+func (m *SessionManager) open(ctx context.Context, key string, method InitMethod, build initRequest) (*Session, error) {
+	m.mu.Lock()
+	closed := m.closed
+	m.mu.Unlock()
+	if closed {
+		return nil, ErrClosed
+	}
+
+	if session := m.Get(key); session != nil {
+		return session, nil
+	}
+
+	var existing *Session
+	session, err := m.sdk.openSession(ctx, method, build, func(session *Session) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.closed {
+			return ErrClosed
+		}
+		if existing = m.sessions[key]; existing != nil && existing.Active() {
+			// Lost a race with another Open for the same key
+			return errSessionExists
+		}
+		m.sessions[key] = session
+		return nil
+	})
+	if errors.Is(err, errSessionExists) {
+		return existing, nil
+	}
+	return session, err
+}
+
+// Get returns the active session for key, or nil
+func (m *SessionManager) Get(key string) *Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session := m.sessions[key]
+	if session == nil || !session.Active() {
+		return nil
+	}
+	return session
+}
+
+// Len returns the number of managed sessions
+func (m *SessionManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// End ends the session for key and stops managing it
+func (m *SessionManager) End(ctx context.Context, key string) error {
+	m.mu.Lock()
+	session := m.sessions[key]
+	delete(m.sessions, key)
+	m.mu.Unlock()
+
+	if session == nil {
+		return ErrNoActiveSession
+	}
+	return session.End(ctx)
+}
+
+// EndAll ends every managed session in parallel and returns the combined errors
+func (m *SessionManager) EndAll(ctx context.Context) error {
+	m.mu.Lock()
+	sessions := m.sessions
+	m.sessions = make(map[string]*Session)
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	var errMu sync.Mutex
+	var errs []error
+	for key, session := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := session.End(ctx); err != nil && !errors.Is(err, ErrNoActiveSession) {
+				errMu.Lock()
+				errs = append(errs, fmt.Errorf("failed to end session %q: %w", key, err))
+				errMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Close stops idle eviction and ends every managed session. Later Open* calls fail with ErrClosed.
+func (m *SessionManager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+
+	m.once.Do(func() { close(m.stop) })
+	<-m.stopped
+	return m.EndAll(ctx)
+}
+
+// run periodically ends idle sessions until stopped
+func (m *SessionManager) run() {
+	defer close(m.stopped)
+
+	ticker := time.NewTicker(m.options.EvictInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.evict(context.Background())
+		}
+	}
+}
+
+// evict ends sessions that have been idle longer than IdleTimeout
+func (m *SessionManager) evict(ctx context.Context) {
+	cutoff := time.Now().Add(-m.options.IdleTimeout)
+
+	idle := make(map[string]*Session)
+	m.mu.Lock()
+	for key, session := range m.sessions {
+		if !session.Active() {
+			delete(m.sessions, key)
+		} else if session.LastUsed().Before(cutoff) {
+			idle[key] = session
+			delete(m.sessions, key)
+		}
+	}
+	m.mu.Unlock()
+
+	for key, session := range idle {
+		if err := session.End(ctx); err != nil && m.options.OnEvictError != nil {
+			m.options.OnEvictError(key, err)
+		}
+	}
+}
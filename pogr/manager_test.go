@@ -0,0 +1,125 @@
+package pogr_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+func newTestManager(t *testing.T, opts *pogr.SessionManagerOptions) (*pogr.SessionManager, func() int) {
+	t.Helper()
+
+	srv, sdk := newTestClient(t, nil)
+	t.Cleanup(func() { sdk.Close(context.Background()) })
+
+	manager, err := pogr.NewSessionManager(sdk, opts)
+	if err != nil {
+		t.Fatalf("NewSessionManager: %v", err)
+	}
+	activeSessions := func() int {
+		active := 0
+		for _, req := range srv.RequestsTo("/init") {
+			if srv.SessionActive(req.SessionID) {
+				active++
+			}
+		}
+		return active
+	}
+	return manager, activeSessions
+}
+
+func TestSessionManagerReusesSessionPerKey(t *testing.T) {
+	manager, activeSessions := newTestManager(t, nil)
+	defer manager.Close(context.Background())
+	ctx := context.Background()
+
+	first, err := manager.OpenWithAssociationID(ctx, "player-1", "assoc-1")
+	if err != nil {
+		t.Fatalf("OpenWithAssociationID: %v", err)
+	}
+	again, err := manager.OpenWithAssociationID(ctx, "player-1", "assoc-1")
+	if err != nil {
+		t.Fatalf("second OpenWithAssociationID: %v", err)
+	}
+	if again != first {
+		t.Error("second Open for the same key started a new session")
+	}
+	if _, err := manager.OpenWithUserJWT(ctx, "player-2", "token"); err != nil {
+		t.Fatalf("OpenWithUserJWT: %v", err)
+	}
+
+	if manager.Len() != 2 || activeSessions() != 2 {
+		t.Fatalf("managing %d sessions with %d active, want 2 and 2", manager.Len(), activeSessions())
+	}
+	if err := manager.EndAll(ctx); err != nil {
+		t.Fatalf("EndAll: %v", err)
+	}
+	if manager.Len() != 0 || activeSessions() != 0 {
+		t.Errorf("managing %d sessions with %d active after EndAll, want none", manager.Len(), activeSessions())
+	}
+}
+
+func TestSessionManagerEvictsIdleSessions(t *testing.T) {
+	manager, activeSessions := newTestManager(t, &pogr.SessionManagerOptions{
+		IdleTimeout:   20 * time.Millisecond,
+		EvictInterval: 5 * time.Millisecond,
+	})
+	defer manager.Close(context.Background())
+
+	if _, err := manager.OpenWithUserJWT(context.Background(), "player-1", "token"); err != nil {
+		t.Fatalf("OpenWithUserJWT: %v", err)
+	}
+	waitFor(t, "eviction", func() bool { return manager.Len() == 0 && activeSessions() == 0 })
+}
+
+func TestSessionManagerRejectsOpenAfterClose(t *testing.T) {
+	manager, activeSessions := newTestManager(t, nil)
+	ctx := context.Background()
+
+	if _, err := manager.OpenWithUserJWT(ctx, "player-1", "token"); err != nil {
+		t.Fatalf("OpenWithUserJWT: %v", err)
+	}
+	if err := manager.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := manager.OpenWithUserJWT(ctx, "player-2", "token"); !errors.Is(err, pogr.ErrClosed) {
+		t.Errorf("Open after Close: got %v, want ErrClosed", err)
+	}
+	if activeSessions() != 0 {
+		t.Errorf("%d sessions active after Close, want none", activeSessions())
+	}
+}
+
+func TestSessionManagerConcurrentOpenKeepsOneSession(t *testing.T) {
+	manager, activeSessions := newTestManager(t, nil)
+	defer manager.Close(context.Background())
+
+	var wg sync.WaitGroup
+	sessions := make([]*pogr.Session, 5)
+	for i := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			session, err := manager.OpenWithUserJWT(context.Background(), "player-1", "token")
+			if err != nil {
+				t.Errorf("OpenWithUserJWT: %v", err)
+			}
+			sessions[i] = session
+		}()
+	}
+	wg.Wait()
+
+	for i, session := range sessions {
+		if session != sessions[0] {
+			t.Errorf("Open %d returned another session for the same key", i)
+		}
+	}
+	if manager.Len() != 1 || activeSessions() != 1 {
+		t.Errorf("managing %d sessions with %d active, want 1 and 1", manager.Len(), activeSessions())
+	}
+}
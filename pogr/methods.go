@@ -7,171 +7,183 @@ import (
 	"fmt"
 )
 
-// InitWithUserJWT initializes a session using a JWT token
-func (sdk *pogrSDK) InitWithUserJWT(userJWT string) (string, error) {
-	headers := map[string]string{
-		"POGR_CLIENT":   sdk.config.ClientKey,
-		"POGR_BUILD":    sdk.config.BuildKey,
-		"Authorization": fmt.Sprintf("Bearer %s", userJWT),
-		"Content-Type":  "application/json",
-	}
-
-	req := &Request{
-		Method:  "POST",
-		URL:     fmt.Sprintf("%s/init", sdk.config.BaseURL),
-		Headers: headers,
-	}
+// InitWithUserJWT starts a session using a JWT token
+func (sdk *pogrSDK) InitWithUserJWT(userJWT string) (*Session, error) {
+	return sdk.InitWithUserJWTContext(context.Background(), userJWT)
+}
 
-	return sdk.handleInitResponse(req)
+// InitWithUserJWTContext is like InitWithUserJWT but uses ctx for cancellation and deadlines
+func (sdk *pogrSDK) InitWithUserJWTContext(ctx context.Context, userJWT string) (*Session, error) {
+	return sdk.openSession(ctx, InitUserJWT, sdk.userJWTInit(userJWT), nil)
 }
 
-// InitWithAssociationID initializes a session using an association ID
-func (sdk *pogrSDK) InitWithAssociationID(associationID string) (string, error) {
-	data := map[string]string{"association_id": associationID}
-	payload, err := json.Marshal(data)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal data: %w", err)
+// userJWTInit builds /init requests for JWT authentication
+func (sdk *pogrSDK) userJWTInit(userJWT string) initRequest {
+	return func(ctx context.Context) (*Request, error) {
+		headers := map[string]string{
+			"POGR_CLIENT":   sdk.config.ClientKey,
+			"POGR_BUILD":    sdk.config.BuildKey,
+			"Authorization": fmt.Sprintf("Bearer %s", userJWT),
+			"Content-Type":  "application/json",
+		}
+
+		return &Request{
+			Method:  "POST",
+			URL:     fmt.Sprintf("%s/init", sdk.config.BaseURL),
+			Headers: headers,
+			Context: ctx,
+		}, nil
 	}
+}
 
-	headers := map[string]string{
-		"POGR_CLIENT":  sdk.config.ClientKey,
-		"POGR_BUILD":   sdk.config.BuildKey,
-		"Content-Type": "application/json",
-	}
+// InitWithAssociationID starts a session using an association ID
+func (sdk *pogrSDK) InitWithAssociationID(associationID string) (*Session, error) {
+	return sdk.InitWithAssociationIDContext(context.Background(), associationID)
+}
 
-	req := &Request{
-		Method:  "POST",
-		URL:     fmt.Sprintf("%s/init", sdk.config.BaseURL),
-		Headers: headers,
-		Body:    payload,
+// InitWithAssociationIDContext is like InitWithAssociationID but uses ctx for cancellation and deadlines
+func (sdk *pogrSDK) InitWithAssociationIDContext(ctx context.Context, associationID string) (*Session, error) {
+	return sdk.openSession(ctx, InitAssociationID, sdk.associationIDInit(associationID), nil)
+}
+
+// associationIDInit builds /init requests for association ID authentication
+func (sdk *pogrSDK) associationIDInit(associationID string) initRequest {
+	return func(ctx context.Context) (*Request, error) {
+		data := map[string]string{"association_id": associationID}
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal data: %w", err)
+		}
+
+		headers := map[string]string{
+			"POGR_CLIENT":  sdk.config.ClientKey,
+			"POGR_BUILD":   sdk.config.BuildKey,
+			"Content-Type": "application/json",
+		}
+
+		return &Request{
+			Method:  "POST",
+			URL:     fmt.Sprintf("%s/init", sdk.config.BaseURL),
+			Headers: headers,
+			Body:    payload,
+			Context: ctx,
+		}, nil
 	}
+}
 
-	return sdk.handleInitResponse(req)
+// InitWithSteamTicket starts a session using a Steam ticket
+func (sdk *pogrSDK) InitWithSteamTicket(steamTicket string) (*Session, error) {
+	return sdk.InitWithSteamTicketContext(context.Background(), steamTicket)
 }
 
-// InitWithSteamTicket initializes a session using a Steam ticket
-func (sdk *pogrSDK) InitWithSteamTicket(steamTicket string) (string, error) {
-	headers := map[string]string{
-		"POGR_CLIENT": sdk.config.ClientKey,
-		"POGR_BUILD":  sdk.config.BuildKey,
-	}
+// InitWithSteamTicketContext is like InitWithSteamTicket but uses ctx for cancellation and deadlines
+func (sdk *pogrSDK) InitWithSteamTicketContext(ctx context.Context, steamTicket string) (*Session, error) {
+	return sdk.openSession(ctx, InitSteamTicket, sdk.steamTicketInit(steamTicket), nil)
+}
 
-	req := &Request{
-		Method:  "POST",
-		URL:     fmt.Sprintf("%s/init?steam_ticket=%s", sdk.config.BaseURL, steamTicket),
-		Headers: headers,
+// steamTicketInit builds /init requests for Steam ticket authentication
+func (sdk *pogrSDK) steamTicketInit(steamTicket string) initRequest {
+	return func(ctx context.Context) (*Request, error) {
+		headers := map[string]string{
+			"POGR_CLIENT": sdk.config.ClientKey,
+			"POGR_BUILD":  sdk.config.BuildKey,
+		}
+
+		return &Request{
+			Method:  "POST",
+			URL:     fmt.Sprintf("%s/init?steam_ticket=%s", sdk.config.BaseURL, steamTicket),
+			Headers: headers,
+			Context: ctx,
+		}, nil
 	}
-
-	return sdk.handleInitResponse(req)
 }
 
 // SendData sends data with optional tags using available authentication method
 func (sdk *pogrSDK) SendData(data interface{}, tags *Tags) (string, error) {
-    payload := DataPayload{
-        Data: data,
-        Tags: tags,
-    }
-
-    jsonData, err := json.Marshal(payload)
-    if err != nil {
-        return "", fmt.Errorf("failed to marshal data: %w", err)
-    }
-
-    headers, err := sdk.getAuthHeaders()
-    if err != nil {
-        return "", err
-    }
-    headers["Content-Type"] = "application/json"
-
-    ctx := context.Background()
-    if sdk.config.Timeout > 0 {
-        var cancel context.CancelFunc
-        ctx, cancel = context.WithTimeout(ctx, sdk.config.Timeout)
-        defer cancel()
-    }
-
-    req := &Request{
-        Method:  "POST",
-        URL:     fmt.Sprintf("%s/data", sdk.config.BaseURL),
-        Headers: headers,
-        Body:    jsonData,
-        Context: ctx,
-    }
-
-    return sdk.handleDataResponse(req)
-}
-
-// EndSession safely ends the current session
-func (sdk *pogrSDK) EndSession() error {
-	sdk.mu.Lock()
-	defer sdk.mu.Unlock()
-
-	if !sdk.state.initialized || sdk.state.sessionID == "" {
-		return ErrNoActiveSession
-	}
+	return sdk.SendDataContext(context.Background(), data, tags)
+}
 
-	headers := map[string]string{
-		"INTAKE_SESSION_ID": sdk.state.sessionID,
-	}
+// SendDataContext is like SendData but uses ctx for cancellation and deadlines
+func (sdk *pogrSDK) SendDataContext(ctx context.Context, data interface{}, tags *Tags) (string, error) {
+	return sdk.sendData(ctx, nil, data, tags)
+}
 
-	req := &Request{
-		Method:  "POST",
-		URL:     fmt.Sprintf("%s/end", sdk.config.BaseURL),
-		Headers: headers,
+// sendData marshals and submits a data payload on behalf of a session, or the client when nil
+func (sdk *pogrSDK) sendData(ctx context.Context, session *Session, data interface{}, tags *Tags) (string, error) {
+	payload := DataPayload{
+		Data: data,
+		Tags: tags,
 	}
 
-	if err := sdk.handleGenericResponse(req); err != nil {
-		return err
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal data: %w", err)
 	}
 
-	sdk.state.sessionID = ""
-	sdk.state.initialized = false
-	return nil
+	return sdk.submit(ctx, session, "/data", jsonData)
 }
 
 // SendEvent sends an event with relevant details and optional user tags
 func (sdk *pogrSDK) SendEvent(event string, subEvent string, eventType string, eventFlag string, eventKey string, eventData map[string]interface{}, tags *Tags) (string, error) {
-	eventPayload := map[string]interface{}{
-		"event":       event,
-		"sub_event":   subEvent,
-		"event_type":  eventType,
-		"event_flag":  eventFlag,
-		"event_key":   eventKey,
-		"event_data":  eventData,
-		"tags":        tags,
-	}
+	return sdk.SendEventContext(context.Background(), event, subEvent, eventType, eventFlag, eventKey, eventData, tags)
+}
 
-	jsonData, err := json.Marshal(eventPayload)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal event data: %w", err)
-	}
+// SendEventContext is like SendEvent but uses ctx for cancellation and deadlines
+func (sdk *pogrSDK) SendEventContext(ctx context.Context, event string, subEvent string, eventType string, eventFlag string, eventKey string, eventData map[string]interface{}, tags *Tags) (string, error) {
+	return sdk.sendEvent(ctx, nil, &Event{
+		Event:     event,
+		SubEvent:  subEvent,
+		EventType: eventType,
+		EventFlag: eventFlag,
+		EventKey:  eventKey,
+		EventData: eventData,
+		Tags:      tags,
+	})
+}
 
-	headers, err := sdk.getAuthHeaders()
-	if err != nil {
+// SendEventObject validates and sends an event built with NewEvent
+func (sdk *pogrSDK) SendEventObject(event *Event) (string, error) {
+	return sdk.SendEventObjectContext(context.Background(), event)
+}
+
+// SendEventObjectContext is like SendEventObject but uses ctx for cancellation and deadlines
+func (sdk *pogrSDK) SendEventObjectContext(ctx context.Context, event *Event) (string, error) {
+	if err := event.Validate(); err != nil {
 		return "", err
 	}
-	headers["Content-Type"] = "application/json"
+	return sdk.sendEvent(ctx, nil, event)
+}
 
-	req := &Request{
-		Method:  "POST",
-		URL:     fmt.Sprintf("%s/event", sdk.config.BaseURL),
-		Headers: headers,
-		Body:    jsonData,
+// sendEvent marshals and submits an event without validating it
+func (sdk *pogrSDK) sendEvent(ctx context.Context, session *Session, event *Event) (string, error) {
+	jsonData, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal event data: %w", err)
 	}
 
-	return sdk.handleDataResponse(req)
+	return sdk.submit(ctx, session, "/event", jsonData)
 }
 
 // SendLog submits a log entry for monitoring and auditing purposes
 func (sdk *pogrSDK) SendLog(service string, environment string, severity string, logType string, logMessage string, data map[string]interface{}, tags *Tags) (string, error) {
-	logPayload := map[string]interface{}{
-		"service":     service,
-		"environment": environment,
-		"severity":    severity,
-		"type":        logType,
-		"log":         logMessage,
-		"data":        data,
-		"tags":        tags,
+	return sdk.SendLogContext(context.Background(), service, environment, severity, logType, logMessage, data, tags)
+}
+
+// SendLogContext is like SendLog but uses ctx for cancellation and deadlines
+func (sdk *pogrSDK) SendLogContext(ctx context.Context, service string, environment string, severity string, logType string, logMessage string, data map[string]interface{}, tags *Tags) (string, error) {
+	return sdk.sendLog(ctx, nil, service, environment, severity, logType, logMessage, data, tags)
+}
+
+// sendLog marshals and submits a log entry
+func (sdk *pogrSDK) sendLog(ctx context.Context, session *Session, service string, environment string, severity string, logType string, logMessage string, data map[string]interface{}, tags *Tags) (string, error) {
+	logPayload := LogEntry{
+		Service:     service,
+		Environment: environment,
+		Severity:    severity,
+		Type:        logType,
+		Log:         logMessage,
+		Data:        data,
+		Tags:        tags,
 	}
 
 	jsonData, err := json.Marshal(logPayload)
@@ -179,29 +191,26 @@ func (sdk *pogrSDK) SendLog(service string, environment string, severity string,
 		return "", fmt.Errorf("failed to marshal log data: %w", err)
 	}
 
-	headers, err := sdk.getAuthHeaders()
-	if err != nil {
-		return "", err
-	}
-	headers["Content-Type"] = "application/json"
-
-	req := &Request{
-		Method:  "POST",
-		URL:     fmt.Sprintf("%s/logs", sdk.config.BaseURL),
-		Headers: headers,
-		Body:    jsonData,
-	}
-
-	return sdk.handleDataResponse(req)
+	return sdk.submit(ctx, session, "/logs", jsonData)
 }
 
 // SendMetrics sends real-time metrics for monitoring purposes
 func (sdk *pogrSDK) SendMetrics(service string, environment string, metrics map[string]interface{}, tags *Tags) (string, error) {
-	metricsPayload := map[string]interface{}{
-		"service":     service,
-		"environment": environment,
-		"metrics":     metrics,
-		"tags":        tags,
+	return sdk.SendMetricsContext(context.Background(), service, environment, metrics, tags)
+}
+
+// SendMetricsContext is like SendMetrics but uses ctx for cancellation and deadlines
+func (sdk *pogrSDK) SendMetricsContext(ctx context.Context, service string, environment string, metrics map[string]interface{}, tags *Tags) (string, error) {
+	return sdk.sendMetrics(ctx, nil, service, environment, metrics, tags)
+}
+
+// sendMetrics marshals and submits a metrics payload
+func (sdk *pogrSDK) sendMetrics(ctx context.Context, session *Session, service string, environment string, metrics map[string]interface{}, tags *Tags) (string, error) {
+	metricsPayload := MetricsEntry{
+		Service:     service,
+		Environment: environment,
+		Metrics:     metrics,
+		Tags:        tags,
 	}
 
 	jsonData, err := json.Marshal(metricsPayload)
@@ -209,24 +218,21 @@ func (sdk *pogrSDK) SendMetrics(service string, environment string, metrics map[
 		return "", fmt.Errorf("failed to marshal metrics data: %w", err)
 	}
 
-	headers, err := sdk.getAuthHeaders()
-	if err != nil {
-		return "", err
-	}
-	headers["Content-Type"] = "application/json"
-
-	req := &Request{
-		Method:  "POST",
-		URL:     fmt.Sprintf("%s/metrics", sdk.config.BaseURL),
-		Headers: headers,
-		Body:    jsonData,
-	}
-
-	return sdk.handleDataResponse(req)
+	return sdk.submit(ctx, session, "/metrics", jsonData)
 }
 
 // SendMonitorData sends system resource usage data
 func (sdk *pogrSDK) SendMonitorData(cpuUsage float64, memoryUsage int, dllsLoaded []string, settings map[string]interface{}) (string, error) {
+	return sdk.SendMonitorDataContext(context.Background(), cpuUsage, memoryUsage, dllsLoaded, settings)
+}
+
+// SendMonitorDataContext is like SendMonitorData but uses ctx for cancellation and deadlines
+func (sdk *pogrSDK) SendMonitorDataContext(ctx context.Context, cpuUsage float64, memoryUsage int, dllsLoaded []string, settings map[string]interface{}) (string, error) {
+	return sdk.sendMonitorData(ctx, nil, cpuUsage, memoryUsage, dllsLoaded, settings)
+}
+
+// sendMonitorData marshals and submits a monitor payload
+func (sdk *pogrSDK) sendMonitorData(ctx context.Context, session *Session, cpuUsage float64, memoryUsage int, dllsLoaded []string, settings map[string]interface{}) (string, error) {
 	monitorPayload := map[string]interface{}{
 		"cpu_usage":    cpuUsage,
 		"memory_usage": memoryUsage,
@@ -239,36 +245,7 @@ func (sdk *pogrSDK) SendMonitorData(cpuUsage float64, memoryUsage int, dllsLoade
 		return "", fmt.Errorf("failed to marshal monitor data: %w", err)
 	}
 
-	headers, err := sdk.getAuthHeaders()
-	if err != nil {
-		return "", err
-	}
-	headers["Content-Type"] = "application/json"
-
-	req := &Request{
-		Method:  "POST",
-		URL:     fmt.Sprintf("%s/monitor", sdk.config.BaseURL),
-		Headers: headers,
-		Body:    jsonData,
-	}
-
-	return sdk.handleDataResponse(req)
-}
-
-
-
-// IsInitialized returns the initialization status
-func (sdk *pogrSDK) IsInitialized() bool {
-	sdk.mu.RLock()
-	defer sdk.mu.RUnlock()
-	return sdk.state.initialized
-}
-
-// GetSessionID returns the current session ID
-func (sdk *pogrSDK) GetSessionID() string {
-	sdk.mu.RLock()
-	defer sdk.mu.RUnlock()
-	return sdk.state.sessionID
+	return sdk.submit(ctx, session, "/monitor", jsonData)
 }
 
 // ValidateTag checks if a tag key is valid
@@ -289,15 +266,90 @@ func (sdk *pogrSDK) ValidateTag(key string) bool {
 	return validTags[key]
 }
 
+// submit delivers a marshaled payload, either right away or through the async pipeline.
+// It returns the intake's data ID, or with async enabled the local item ID that the
+// item's AsyncResult carries.
+func (sdk *pogrSDK) submit(ctx context.Context, session *Session, endpoint string, body []byte) (string, error) {
+	if sdk.closed.Load() {
+		return "", ErrClosed
+	}
+	if sdk.async != nil {
+		return sdk.async.enqueue(asyncItem{session: session, endpoint: endpoint, body: body})
+	}
+	return sdk.deliver(ctx, session, endpoint, body)
+}
+
+// deliver sends a marshaled payload, through the outbox when enabled, and returns its data ID
+func (sdk *pogrSDK) deliver(ctx context.Context, session *Session, endpoint string, body []byte) (string, error) {
+	if sdk.outbox != nil {
+		return sdk.outbox.send(ctx, session, endpoint, body)
+	}
+
+	resp, err := sdk.post(ctx, session, endpoint, body)
+	if err != nil {
+		return "", err
+	}
+	return decodeDataResponse(endpoint, resp)
+}
+
+// post sends a marshaled JSON payload to an intake endpoint, renewing an expired session once.
+// A nil session authenticates with the client's keys. When renewal fails the error joins the
+// intake's rejection with the reason renewal failed.
+func (sdk *pogrSDK) post(ctx context.Context, session *Session, endpoint string, body []byte) (*Response, error) {
+	return sdk.postAs(ctx, session, endpoint, "application/json", body)
+}
+
+// postAs is like post but sends the body with the given content type
+func (sdk *pogrSDK) postAs(ctx context.Context, session *Session, endpoint string, contentType string, body []byte) (*Response, error) {
+	resp, sessionID, err := sdk.postOnce(ctx, session, endpoint, contentType, body)
+	if err != nil || sessionID == "" || !isSessionExpired(resp) {
+		return resp, err
+	}
+
+	if renewErr := session.renew(ctx, sessionID); renewErr != nil {
+		return nil, errors.Join(newAPIError(endpoint, resp, ""), fmt.Errorf("failed to renew session: %w", renewErr))
+	}
+
+	resp, _, err = sdk.postOnce(ctx, session, endpoint, contentType, body)
+	return resp, err
+}
+
+// postOnce sends a marshaled payload and reports the session ID it authenticated with, if any
+func (sdk *pogrSDK) postOnce(ctx context.Context, session *Session, endpoint string, contentType string, body []byte) (*Response, string, error) {
+	var headers map[string]string
+	var err error
+	if session != nil {
+		headers, err = session.authHeaders()
+	} else {
+		headers, err = sdk.getAuthHeaders()
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	headers["Content-Type"] = contentType
+
+	ctx, cancel := sdk.withTimeout(ctx)
+	defer cancel()
+
+	req := &Request{
+		Method:  "POST",
+		URL:     sdk.config.BaseURL + endpoint,
+		Headers: headers,
+		Body:    body,
+		Context: ctx,
+	}
+
+	resp, err := sdk.do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	return resp, headers["INTAKE_SESSION_ID"], nil
+}
+
 func (sdk *pogrSDK) getAuthHeaders() (map[string]string, error) {
 	headers := make(map[string]string)
 
 	// Check auth methods in priority order
-	if sessionID := sdk.getSessionID(); sessionID != "" {
-		headers["INTAKE_SESSION_ID"] = sessionID
-		return headers, nil
-	}
-
 	if sdk.hasAccessKeyAuth() {
 		headers["ACCESS_KEY"] = sdk.config.AccessKey
 		headers["SECRET_KEY"] = sdk.config.SecretKey
@@ -313,10 +365,12 @@ func (sdk *pogrSDK) getAuthHeaders() (map[string]string, error) {
 	return nil, errors.New("no valid authentication method available")
 }
 
-func (sdk *pogrSDK) getSessionID() string {
-	sdk.mu.RLock()
-	defer sdk.mu.RUnlock()
-	return sdk.state.sessionID
+// withTimeout applies the configured timeout to a context
+func (sdk *pogrSDK) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if sdk.config.Timeout > 0 {
+		return context.WithTimeout(ctx, sdk.config.Timeout)
+	}
+	return context.WithCancel(ctx)
 }
 
 func (sdk *pogrSDK) hasAccessKeyAuth() bool {
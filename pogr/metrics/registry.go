@@ -0,0 +1,259 @@
+// Package metrics aggregates counters, gauges and histograms locally and reports them through SendMetrics.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+// Options configures a Registry
+type Options struct {
+	Service       string          // Service name sent with every report
+	Environment   string          // Environment name sent with every report
+	FlushInterval time.Duration   // Aggregation window, defaults to ten seconds
+	OnError       func(err error) // Called when a background flush fails
+}
+
+// metricKey identifies a metric by name and tags
+type metricKey struct {
+	name string
+	tags pogr.Tags
+}
+
+// Registry owns metrics and flushes their aggregates once per window
+type Registry struct {
+	sdk  pogr.POGRService
+	opts Options
+
+	mu         sync.Mutex
+	counters   map[metricKey]*Counter
+	gauges     map[metricKey]*Gauge
+	histograms map[metricKey]*Histogram
+	kinds      map[metricKey]string // Kind of metric registered under each key
+
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewRegistry creates a Registry that flushes in the background until closed
+func NewRegistry(sdk pogr.POGRService, opts *Options) *Registry {
+	settings := Options{FlushInterval: 10 * time.Second}
+	if opts != nil {
+		settings.Service = opts.Service
+		settings.Environment = opts.Environment
+		settings.OnError = opts.OnError
+		if opts.FlushInterval > 0 {
+			settings.FlushInterval = opts.FlushInterval
+		}
+	}
+
+	r := &Registry{
+		sdk:        sdk,
+		opts:       settings,
+		counters:   make(map[metricKey]*Counter),
+		gauges:     make(map[metricKey]*Gauge),
+		histograms: make(map[metricKey]*Histogram),
+		kinds:      make(map[metricKey]string),
+		stop:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// ErrKindConflict is returned when a name and tags are already registered as another kind of metric
+var ErrKindConflict = errors.New("metrics: name already registered as another kind")
+
+// Counter returns the counter with the given name and tags, creating it on first use.
+// It fails with ErrKindConflict if a gauge or histogram already uses the name and tags.
+func (r *Registry) Counter(name string, tags *pogr.Tags) (*Counter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := keyFor(name, tags)
+	counter, ok := r.counters[key]
+	if !ok {
+		if err := r.claim(key, "counter"); err != nil {
+			return nil, err
+		}
+		counter = &Counter{}
+		r.counters[key] = counter
+	}
+	return counter, nil
+}
+
+// Gauge returns the gauge with the given name and tags, creating it on first use.
+// It fails with ErrKindConflict if a counter or histogram already uses the name and tags.
+func (r *Registry) Gauge(name string, tags *pogr.Tags) (*Gauge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := keyFor(name, tags)
+	gauge, ok := r.gauges[key]
+	if !ok {
+		if err := r.claim(key, "gauge"); err != nil {
+			return nil, err
+		}
+		gauge = &Gauge{}
+		r.gauges[key] = gauge
+	}
+	return gauge, nil
+}
+
+// Histogram returns the histogram with the given name and tags, creating it on
+// first use with the given bucket upper bounds (DefaultBuckets if empty).
+// It fails with ErrKindConflict if a counter or gauge already uses the name and tags.
+func (r *Registry) Histogram(name string, tags *pogr.Tags, buckets []float64) (*Histogram, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := keyFor(name, tags)
+	histogram, ok := r.histograms[key]
+	if !ok {
+		if err := r.claim(key, "histogram"); err != nil {
+			return nil, err
+		}
+		histogram = newHistogram(buckets)
+		r.histograms[key] = histogram
+	}
+	return histogram, nil
+}
+
+// MustCounter is like Counter but panics on ErrKindConflict
+func (r *Registry) MustCounter(name string, tags *pogr.Tags) *Counter {
+	return must(r.Counter(name, tags))
+}
+
+// MustGauge is like Gauge but panics on ErrKindConflict
+func (r *Registry) MustGauge(name string, tags *pogr.Tags) *Gauge {
+	return must(r.Gauge(name, tags))
+}
+
+// MustHistogram is like Histogram but panics on ErrKindConflict
+func (r *Registry) MustHistogram(name string, tags *pogr.Tags, buckets []float64) *Histogram {
+	return must(r.Histogram(name, tags, buckets))
+}
+
+func must[T any](metric T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return metric
+}
+
+// claim records key as a metric of kind. Metrics sharing a name and tags would overwrite
+// each other in the report, so a key registered as another kind is refused. Called with mu held.
+func (r *Registry) claim(key metricKey, kind string) error {
+	if existing, ok := r.kinds[key]; ok && existing != kind {
+		return fmt.Errorf("%w: %q is a %s with these tags", ErrKindConflict, key.name, existing)
+	}
+	r.kinds[key] = kind
+	return nil
+}
+
+// report holds the values sent for one tag set and how to put them back if the send fails
+type report struct {
+	values  map[string]interface{}
+	restore []func()
+}
+
+// Flush reports the current window immediately, one SendMetrics call per distinct tag set
+// with data, so an idle window sends nothing. Values whose send fails are merged back into
+// the next window.
+func (r *Registry) Flush(ctx context.Context) error {
+	var errs []error
+	for tags, report := range r.collect() {
+		var tagsArg *pogr.Tags
+		if tags != (pogr.Tags{}) {
+			tagsArg = &tags
+		}
+		if _, err := r.sdk.SendMetricsContext(ctx, r.opts.Service, r.opts.Environment, report.values, tagsArg); err != nil {
+			errs = append(errs, err)
+			for _, restore := range report.restore {
+				restore()
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes the final window and stops the background flush loop
+func (r *Registry) Close(ctx context.Context) error {
+	r.once.Do(func() { close(r.stop) })
+
+	select {
+	case <-r.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return r.Flush(ctx)
+}
+
+func (r *Registry) run() {
+	defer close(r.stopped)
+
+	ticker := time.NewTicker(r.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			if err := r.Flush(context.Background()); err != nil && r.opts.OnError != nil {
+				r.opts.OnError(err)
+			}
+		}
+	}
+}
+
+// collect snapshots and resets every metric, grouped by tag set
+func (r *Registry) collect() map[pogr.Tags]*report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	groups := make(map[pogr.Tags]*report)
+	add := func(key metricKey, value interface{}, restore func()) {
+		group, ok := groups[key.tags]
+		if !ok {
+			group = &report{values: make(map[string]interface{})}
+			groups[key.tags] = group
+		}
+		group.values[key.name] = value
+		if restore != nil {
+			group.restore = append(group.restore, restore)
+		}
+	}
+
+	for key, counter := range r.counters {
+		// An idle counter has nothing to report this window
+		if value := counter.collect(); value != 0 {
+			add(key, value, func() { counter.restore(value) })
+		}
+	}
+	for key, gauge := range r.gauges {
+		if value, ok := gauge.collect(); ok {
+			add(key, value, nil)
+		}
+	}
+	for key, histogram := range r.histograms {
+		if window, ok := histogram.collect(); ok {
+			add(key, histogram.summarize(window), func() { histogram.restore(window) })
+		}
+	}
+	return groups
+}
+
+func keyFor(name string, tags *pogr.Tags) metricKey {
+	key := metricKey{name: name}
+	if tags != nil {
+		key.tags = *tags
+	}
+	return key
+}
@@ -0,0 +1,163 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/metrics"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+// newTestRegistry returns a registry that only flushes on demand, sending to a fake intake
+func newTestRegistry(t *testing.T) (*pogrtest.Server, *metrics.Registry) {
+	t.Helper()
+
+	srv := pogrtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	registry := metrics.NewRegistry(pogr.NewPOGRSDK(srv.Config()), &metrics.Options{
+		Service:       "game-server",
+		Environment:   "test",
+		FlushInterval: time.Hour,
+	})
+	t.Cleanup(func() { registry.Close(context.Background()) })
+	return srv, registry
+}
+
+// lastMetrics returns the metrics map of the last accepted /metrics request
+func lastMetrics(t *testing.T, srv *pogrtest.Server) map[string]interface{} {
+	t.Helper()
+
+	requests := srv.RequestsTo("/metrics")
+	if len(requests) == 0 || requests[len(requests)-1].Status != http.StatusOK {
+		t.Fatalf("no accepted /metrics request among %d", len(requests))
+	}
+	values, _ := requests[len(requests)-1].Payload["metrics"].(map[string]interface{})
+	return values
+}
+
+func TestFlushAggregatesWindow(t *testing.T) {
+	srv, registry := newTestRegistry(t)
+
+	for range 1000 {
+		registry.MustCounter("frames", nil).Inc()
+	}
+	registry.MustGauge("players", nil).Set(12)
+
+	if err := registry.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got := len(srv.RequestsTo("/metrics")); got != 1 {
+		t.Fatalf("got %d /metrics requests, want 1", got)
+	}
+
+	values := lastMetrics(t, srv)
+	if values["frames"] != 1000.0 || values["players"] != 12.0 {
+		t.Errorf("got frames %v and players %v, want 1000 and 12", values["frames"], values["players"])
+	}
+}
+
+func TestFlushSkipsIdleWindows(t *testing.T) {
+	srv, registry := newTestRegistry(t)
+
+	registry.MustCounter("kills", nil).Inc()
+	registry.MustHistogram("tick_ms", nil, nil).Observe(5)
+	for range 3 {
+		if err := registry.Flush(context.Background()); err != nil {
+			t.Fatalf("Flush: %v", err)
+		}
+	}
+
+	if got := len(srv.RequestsTo("/metrics")); got != 1 {
+		t.Errorf("got %d /metrics requests, want 1 for the only window with data", got)
+	}
+}
+
+func TestFailedFlushKeepsWindow(t *testing.T) {
+	srv, registry := newTestRegistry(t)
+
+	registry.MustCounter("kills", nil).Add(3)
+	registry.MustHistogram("tick_ms", nil, []float64{10, 20}).Observe(5)
+
+	srv.FailNext("/metrics", http.StatusBadRequest, "rejected")
+	if err := registry.Flush(context.Background()); err == nil {
+		t.Fatal("Flush succeeded despite the intake rejecting it")
+	}
+
+	registry.MustCounter("kills", nil).Add(2)
+	registry.MustHistogram("tick_ms", nil, nil).Observe(15)
+	if err := registry.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	values := lastMetrics(t, srv)
+	if values["kills"] != 5.0 {
+		t.Errorf("got kills %v, want the failed window's 3 plus 2", values["kills"])
+	}
+	tick, _ := values["tick_ms"].(map[string]interface{})
+	if tick["count"] != 2.0 || tick["min"] != 5.0 || tick["max"] != 15.0 {
+		t.Errorf("got tick_ms %v, want both windows merged", tick)
+	}
+}
+
+func TestHistogramBucketsAreCumulative(t *testing.T) {
+	srv, registry := newTestRegistry(t)
+
+	histogram := registry.MustHistogram("frame_ms", nil, []float64{10, 20, 30})
+	for _, value := range []float64{5, 15, 15, 25, 100} {
+		histogram.Observe(value)
+	}
+	if err := registry.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	frame, _ := lastMetrics(t, srv)["frame_ms"].(map[string]interface{})
+	buckets, _ := frame["buckets"].(map[string]interface{})
+	want := map[string]float64{"le_10": 1, "le_20": 3, "le_30": 4, "le_+Inf": 5}
+	for key, count := range want {
+		if buckets[key] != count {
+			t.Errorf("bucket %s is %v, want %v", key, buckets[key], count)
+		}
+	}
+}
+
+func TestRegistryRejectsNameOfAnotherKind(t *testing.T) {
+	srv, registry := newTestRegistry(t)
+
+	registry.MustCounter("x", nil).Add(5)
+	if gauge, err := registry.Gauge("x", nil); !errors.Is(err, metrics.ErrKindConflict) || gauge != nil {
+		t.Errorf("Gauge reusing a counter's name and tags: got %v, %v, want ErrKindConflict", gauge, err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("MustGauge reused a counter's name and tags without panicking")
+			}
+		}()
+		registry.MustGauge("x", nil)
+	}()
+
+	// The same name under other tags is a separate metric
+	registry.MustGauge("x", &pogr.Tags{AssociationID: "match-1"}).Set(7)
+
+	if err := registry.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	untagged := 0
+	for _, req := range srv.RequestsTo("/metrics") {
+		if req.Payload["tags"] != nil {
+			continue
+		}
+		untagged++
+		if values, _ := req.Payload["metrics"].(map[string]interface{}); values["x"] != float64(5) {
+			t.Errorf("untagged x = %v, want the counter's 5", values["x"])
+		}
+	}
+	if untagged != 1 {
+		t.Errorf("got %d untagged /metrics requests, want 1", untagged)
+	}
+}
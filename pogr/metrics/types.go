@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBuckets are histogram upper bounds suited to frame and tick durations in milliseconds
+var DefaultBuckets = []float64{1, 2, 4, 8, 16, 33, 50, 100, 250, 500, 1000}
+
+// Counter accumulates a value that only increases; each flush reports the increase during the window
+type Counter struct {
+	bits atomic.Uint64
+}
+
+// Inc adds one to the counter
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds a non-negative delta to the counter
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	addFloat(&c.bits, delta)
+}
+
+// collect returns the value accumulated since the last flush and resets it
+func (c *Counter) collect() float64 {
+	return math.Float64frombits(c.bits.Swap(0))
+}
+
+// restore adds back a collected value that could not be sent
+func (c *Counter) restore(value float64) {
+	addFloat(&c.bits, value)
+}
+
+// Gauge holds a value that can go up and down; each flush reports the latest value
+type Gauge struct {
+	bits atomic.Uint64
+	set  atomic.Bool
+}
+
+// Set replaces the gauge value
+func (g *Gauge) Set(value float64) {
+	g.bits.Store(math.Float64bits(value))
+	g.set.Store(true)
+}
+
+// Add adjusts the gauge value by delta
+func (g *Gauge) Add(delta float64) {
+	addFloat(&g.bits, delta)
+	g.set.Store(true)
+}
+
+// collect returns the current value and whether the gauge was ever set
+func (g *Gauge) collect() (float64, bool) {
+	return math.Float64frombits(g.bits.Load()), g.set.Load()
+}
+
+// Histogram summarizes observations over each flush window. Bucket counts are
+// cumulative: "le_<bound>" counts every observation less than or equal to bound.
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	window  histogramWindow
+}
+
+// histogramWindow holds the observations of one flush window
+type histogramWindow struct {
+	counts []uint64 // One per bucket plus an overflow bucket, not cumulative
+	count  uint64
+	sum    float64
+	min    float64
+	max    float64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &Histogram{
+		buckets: sorted,
+		window:  histogramWindow{counts: make([]uint64, len(sorted)+1)},
+	}
+}
+
+// Observe records a single observation
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w := &h.window
+	if w.count == 0 || value < w.min {
+		w.min = value
+	}
+	if w.count == 0 || value > w.max {
+		w.max = value
+	}
+	w.count++
+	w.sum += value
+	w.counts[sort.SearchFloat64s(h.buckets, value)]++
+}
+
+// collect returns the current window and starts a new one; ok is false if nothing was observed
+func (h *Histogram) collect() (window histogramWindow, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.window.count == 0 {
+		return histogramWindow{}, false
+	}
+	window = h.window
+	h.window = histogramWindow{counts: make([]uint64, len(h.buckets)+1)}
+	return window, true
+}
+
+// restore merges back a collected window that could not be sent
+func (h *Histogram) restore(window histogramWindow) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w := &h.window
+	if w.count == 0 || window.min < w.min {
+		w.min = window.min
+	}
+	if w.count == 0 || window.max > w.max {
+		w.max = window.max
+	}
+	w.count += window.count
+	w.sum += window.sum
+	for i, n := range window.counts {
+		w.counts[i] += n
+	}
+}
+
+// summarize reports a window with cumulative bucket counts
+func (h *Histogram) summarize(window histogramWindow) map[string]interface{} {
+	buckets := make(map[string]uint64, len(window.counts))
+	var cumulative uint64
+	for i, n := range window.counts {
+		cumulative += n
+		bound := "+Inf"
+		if i < len(h.buckets) {
+			bound = strconv.FormatFloat(h.buckets[i], 'g', -1, 64)
+		}
+		buckets["le_"+bound] = cumulative
+	}
+
+	return map[string]interface{}{
+		"count":   window.count,
+		"sum":     window.sum,
+		"min":     window.min,
+		"max":     window.max,
+		"mean":    window.sum / float64(window.count),
+		"buckets": buckets,
+	}
+}
+
+// addFloat atomically adds delta to a float64 stored as bits
+func addFloat(bits *atomic.Uint64, delta float64) {
+	for {
+		old := bits.Load()
+		updated := math.Float64bits(math.Float64frombits(old) + delta)
+		if bits.CompareAndSwap(old, updated) {
+			return
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package monitor samples process and Go runtime statistics and reports them through SendMonitorData.
+package monitor
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+// Sample holds one snapshot of process and runtime statistics
+type Sample struct {
+	CPUPercent    float64                // Process CPU usage since the previous sample, 100 per fully used core
+	RSSBytes      int                    // Resident set size of the process in bytes
+	SharedObjects []string               // Shared objects mapped into the process
+	Runtime       map[string]interface{} // Go runtime statistics
+}
+
+// Collector samples statistics for the current process
+type Collector struct {
+	mu       sync.Mutex
+	lastCPU  time.Duration
+	lastWall time.Time
+}
+
+// NewCollector creates a Collector; the first sample reports CPU usage since this call
+func NewCollector() *Collector {
+	c := &Collector{lastWall: time.Now()}
+	if cpu, err := processCPUTime(); err == nil {
+		c.lastCPU = cpu
+	}
+	return c
+}
+
+// Collect takes a sample. Process statistics are only available on Linux;
+// elsewhere they are left empty and only runtime statistics are reported.
+func (c *Collector) Collect() (*Sample, error) {
+	sample := &Sample{Runtime: runtimeStats()}
+
+	cpuPercent, err := c.cpuPercent()
+	if err != nil && !errors.Is(err, errors.ErrUnsupported) {
+		return nil, err
+	}
+	sample.CPUPercent = cpuPercent
+
+	rss, err := processRSS()
+	if err != nil && !errors.Is(err, errors.ErrUnsupported) {
+		return nil, err
+	}
+	sample.RSSBytes = rss
+
+	objects, err := sharedObjects()
+	if err != nil && !errors.Is(err, errors.ErrUnsupported) {
+		return nil, err
+	}
+	sample.SharedObjects = objects
+
+	return sample, nil
+}
+
+// cpuPercent computes CPU usage since the previous call
+func (c *Collector) cpuPercent() (float64, error) {
+	cpu, err := processCPUTime()
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	wall := now.Sub(c.lastWall)
+	used := cpu - c.lastCPU
+	c.lastCPU = cpu
+	c.lastWall = now
+
+	if wall <= 0 {
+		return 0, nil
+	}
+	return float64(used) / float64(wall) * 100, nil
+}
+
+// runtimeMetrics lists the runtime/metrics samples reported, keyed by their settings name
+var runtimeMetrics = map[string]string{
+	"heap_object_bytes": "/memory/classes/heap/objects:bytes",
+	"heap_goal_bytes":   "/gc/heap/goal:bytes",
+	"total_bytes":       "/memory/classes/total:bytes",
+	"goroutines":        "/sched/goroutines:goroutines",
+	"gc_cycles":         "/gc/cycles/total:gc-cycles",
+	"gc_pauses":         "/sched/pauses/total/gc:seconds",
+}
+
+// runtimeStats reads Go runtime statistics
+func runtimeStats() map[string]interface{} {
+	samples := make([]metrics.Sample, 0, len(runtimeMetrics))
+	names := make([]string, 0, len(runtimeMetrics))
+	for name, metric := range runtimeMetrics {
+		samples = append(samples, metrics.Sample{Name: metric})
+		names = append(names, name)
+	}
+	metrics.Read(samples)
+
+	stats := map[string]interface{}{
+		"go_version": runtime.Version(),
+		"gomaxprocs": runtime.GOMAXPROCS(0),
+	}
+	for i, sample := range samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			stats[names[i]] = sample.Value.Uint64()
+		case metrics.KindFloat64:
+			stats[names[i]] = sample.Value.Float64()
+		case metrics.KindFloat64Histogram:
+			count, max := summarizeHistogram(sample.Value.Float64Histogram())
+			stats[names[i]+"_count"] = count
+			stats[names[i]+"_max_seconds"] = max
+		}
+	}
+	return stats
+}
+
+// summarizeHistogram returns the total count and an upper estimate of the largest observation
+func summarizeHistogram(h *metrics.Float64Histogram) (uint64, float64) {
+	var count uint64
+	var max float64
+	for i, n := range h.Counts {
+		if n == 0 {
+			continue
+		}
+		count += n
+		// Buckets[i+1] is the upper bound of Counts[i]; the last one may be +Inf
+		if upper := h.Buckets[i+1]; !math.IsInf(upper, 1) {
+			max = upper
+		} else {
+			max = h.Buckets[i]
+		}
+	}
+	return count, max
+}
@@ -0,0 +1,47 @@
+package monitor_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr/monitor"
+)
+
+func TestCollectorReportsRuntimeStats(t *testing.T) {
+	sample, err := monitor.NewCollector().Collect()
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	for _, name := range []string{"go_version", "gomaxprocs", "heap_object_bytes", "goroutines", "gc_pauses_count"} {
+		if _, ok := sample.Runtime[name]; !ok {
+			t.Errorf("runtime stats are missing %q: %v", name, sample.Runtime)
+		}
+	}
+	if sample.CPUPercent < 0 {
+		t.Errorf("CPUPercent = %v, want at least 0", sample.CPUPercent)
+	}
+}
+
+func TestCollectorReportsProcessStats(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("process statistics are only collected on Linux")
+	}
+
+	collector := monitor.NewCollector()
+	// Burn well over one clock tick of CPU so the sample has usage to report
+	for start := time.Now(); time.Since(start) < 100*time.Millisecond; {
+	}
+
+	sample, err := collector.Collect()
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if sample.RSSBytes <= 0 {
+		t.Errorf("RSSBytes = %d, want a positive size", sample.RSSBytes)
+	}
+	if sample.CPUPercent <= 0 {
+		t.Errorf("CPUPercent = %v after a busy loop, want a positive usage", sample.CPUPercent)
+	}
+}
@@ -0,0 +1,98 @@
+//go:build linux
+
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is the kernel USER_HZ, which is 100 on every supported Linux architecture
+const clockTicks = 100
+
+// processCPUTime returns the user and system CPU time consumed by the process
+func processCPUTime() (time.Duration, error) {
+	data, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read process stat: %w", err)
+	}
+
+	// The command name may contain spaces, so parse after its closing parenthesis
+	stat := string(data)
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("malformed process stat")
+	}
+	fields := strings.Fields(stat[end+1:])
+	// utime and stime are fields 14 and 15 of the full line, 12 and 13 after the command name
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("malformed process stat")
+	}
+
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed process utime: %w", err)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed process stime: %w", err)
+	}
+
+	return time.Duration(utime+stime) * time.Second / clockTicks, nil
+}
+
+// processRSS returns the resident set size of the process in bytes
+func processRSS() (int, error) {
+	data, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read process statm: %w", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("malformed process statm")
+	}
+	pages, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, fmt.Errorf("malformed process rss: %w", err)
+	}
+
+	return pages * os.Getpagesize(), nil
+}
+
+// sharedObjects lists the shared objects mapped into the process
+func sharedObjects() ([]string, error) {
+	f, err := os.Open("/proc/self/maps")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read process maps: %w", err)
+	}
+	defer f.Close()
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+		path := fields[5]
+		if strings.HasSuffix(path, ".so") || strings.Contains(path, ".so.") {
+			seen[path] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read process maps: %w", err)
+	}
+
+	objects := make([]string, 0, len(seen))
+	for path := range seen {
+		objects = append(objects, path)
+	}
+	sort.Strings(objects)
+	return objects, nil
+}
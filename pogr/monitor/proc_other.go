@@ -0,0 +1,20 @@
+//go:build !linux
+
+package monitor
+
+import (
+	"errors"
+	"time"
+)
+
+func processCPUTime() (time.Duration, error) {
+	return 0, errors.ErrUnsupported
+}
+
+func processRSS() (int, error) {
+	return 0, errors.ErrUnsupported
+}
+
+func sharedObjects() ([]string, error) {
+	return nil, errors.ErrUnsupported
+}
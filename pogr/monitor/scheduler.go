@@ -0,0 +1,94 @@
+package monitor
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+// SchedulerOptions configures a Scheduler
+type SchedulerOptions struct {
+	Interval  time.Duration          // Time between reports, defaults to one minute
+	Collector *Collector             // Collector to sample from, a new one if nil
+	Settings  map[string]interface{} // Extra settings merged into every report
+	OnError   func(err error)        // Called when sampling or reporting fails
+}
+
+// Scheduler reports samples through SendMonitorData on an interval
+type Scheduler struct {
+	sdk    pogr.POGRService
+	opts   SchedulerOptions
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Start begins reporting in the background until Stop is called
+func Start(sdk pogr.POGRService, opts *SchedulerOptions) *Scheduler {
+	settings := SchedulerOptions{Interval: time.Minute}
+	if opts != nil {
+		settings.Collector = opts.Collector
+		settings.Settings = opts.Settings
+		settings.OnError = opts.OnError
+		if opts.Interval > 0 {
+			settings.Interval = opts.Interval
+		}
+	}
+	if settings.Collector == nil {
+		settings.Collector = NewCollector()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		sdk:    sdk,
+		opts:   settings,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go s.run(ctx)
+	return s
+}
+
+// Stop halts reporting and waits for an in-flight report to finish
+func (s *Scheduler) Stop() {
+	s.once.Do(s.cancel)
+	<-s.done
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.report(ctx); err != nil && ctx.Err() == nil && s.opts.OnError != nil {
+				s.opts.OnError(err)
+			}
+		}
+	}
+}
+
+// report collects a sample and sends it
+func (s *Scheduler) report(ctx context.Context) error {
+	sample, err := s.opts.Collector.Collect()
+	if err != nil {
+		return err
+	}
+
+	settings := maps.Clone(s.opts.Settings)
+	if settings == nil {
+		settings = make(map[string]interface{})
+	}
+	maps.Copy(settings, sample.Runtime)
+
+	_, err = s.sdk.SendMonitorDataContext(ctx, sample.CPUPercent, sample.RSSBytes, sample.SharedObjects, settings)
+	return err
+}
@@ -0,0 +1,47 @@
+package monitor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/monitor"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+func TestSchedulerReportsOnInterval(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	scheduler := monitor.Start(pogr.NewPOGRSDK(srv.Config()), &monitor.SchedulerOptions{
+		Interval: 20 * time.Millisecond,
+		Settings: map[string]interface{}{"region": "eu"},
+		OnError:  func(err error) { t.Errorf("report failed: %v", err) },
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(srv.RequestsTo("/monitor")) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	scheduler.Stop()
+
+	requests := srv.RequestsTo("/monitor")
+	if len(requests) < 2 {
+		t.Fatalf("got %d /monitor requests, want at least 2", len(requests))
+	}
+	payload := requests[0].Payload
+	settings, _ := payload["settings"].(map[string]interface{})
+	if settings["region"] != "eu" || settings["go_version"] == nil {
+		t.Errorf("settings = %v, want region eu merged with runtime stats", settings)
+	}
+	if _, ok := payload["cpu_usage"].(float64); !ok {
+		t.Errorf("cpu_usage = %v, want a number", payload["cpu_usage"])
+	}
+
+	// No reports after Stop returns
+	stopped := len(srv.RequestsTo("/monitor"))
+	time.Sleep(60 * time.Millisecond)
+	if got := len(srv.RequestsTo("/monitor")); got != stopped {
+		t.Errorf("got %d /monitor requests after Stop, want %d", got, stopped)
+	}
+}
@@ -0,0 +1,492 @@
+package pogr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DropPolicy decides which payloads are discarded when the outbox is full
+type DropPolicy int
+
+const (
+	DropOldest DropPolicy = iota // Discard the oldest queued payloads to make room
+	DropNewest                   // Reject the incoming payload
+)
+
+// Durability controls how aggressively the outbox syncs writes to disk
+type Durability int
+
+const (
+	DurabilityFile Durability = iota // Fsync every payload file; the default
+	DurabilityNone                   // Leave flushing to the operating system
+	DurabilityFull                   // Fsync every payload file and the outbox directory
+)
+
+// OutboxConfig holds settings for the disk-backed outbox
+type OutboxConfig struct {
+	Dir            string        // Directory holding queued payloads; required, and only one client may use it at a time
+	MaxBytes       int64         // Maximum total size of queued payloads; 0 uses the default, negative for unlimited
+	MaxAge         time.Duration // Payloads older than this are discarded; 0 uses the default, negative for unlimited
+	DropPolicy     DropPolicy
+	Durability     Durability
+	ReplayInterval time.Duration // How often queued payloads are retried in the background
+}
+
+// DefaultOutboxConfig returns default outbox settings. Dir has no default: each
+// client needs a directory of its own.
+func DefaultOutboxConfig() *OutboxConfig {
+	return &OutboxConfig{
+		MaxBytes:       64 << 20,
+		MaxAge:         7 * 24 * time.Hour,
+		DropPolicy:     DropOldest,
+		Durability:     DurabilityFile,
+		ReplayInterval: 30 * time.Second,
+	}
+}
+
+// outboxRecord is the on-disk representation of a queued payload
+type outboxRecord struct {
+	SessionID string    `json:"session_id,omitempty"` // Session the payload was sent on, if any
+	Endpoint  string    `json:"endpoint"`
+	Body      []byte    `json:"body"`
+	Created   time.Time `json:"created"`
+}
+
+// outboxEntry tracks a record file without holding its payload in memory
+type outboxEntry struct {
+	seq     uint64
+	size    int64
+	created time.Time
+}
+
+// outboxResult is the outcome of delivering a record
+type outboxResult struct {
+	dataID string
+	err    error
+}
+
+// outbox persists payloads to disk before sending and replays them in order
+type outbox struct {
+	sdk    *pogrSDK
+	config *OutboxConfig
+
+	mu        sync.Mutex // Guards the fields below; not held while a record is delivered
+	opened    bool
+	unlock    func() // Releases the directory lock, nil while not held
+	entries   []outboxEntry
+	size      int64
+	nextSeq   uint64
+	waiters   map[uint64]chan outboxResult // Sends waiting for the outcome of their record
+	replaying chan struct{}                // Closed when the running replay ends, nil when none runs
+	inflight  uint64                       // Record being delivered, 0 when none
+
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// newOutboxConfig fills unset outbox settings with defaults
+func newOutboxConfig(config *OutboxConfig) *OutboxConfig {
+	settings := DefaultOutboxConfig()
+	if config != nil {
+		settings.DropPolicy = config.DropPolicy
+		settings.Durability = config.Durability
+		if config.MaxBytes != 0 {
+			settings.MaxBytes = config.MaxBytes
+		}
+		if config.MaxAge != 0 {
+			settings.MaxAge = config.MaxAge
+		}
+		if config.Dir != "" {
+			settings.Dir = config.Dir
+		}
+		if config.ReplayInterval > 0 {
+			settings.ReplayInterval = config.ReplayInterval
+		}
+	}
+	return settings
+}
+
+func newOutbox(sdk *pogrSDK, config *OutboxConfig) *outbox {
+	settings := newOutboxConfig(config)
+
+	o := &outbox{
+		sdk:     sdk,
+		config:  settings,
+		waiters: make(map[uint64]chan outboxResult),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go o.run()
+	return o
+}
+
+// send persists a payload, then waits until every queued payload up to and including it
+// has been delivered, delivering them itself unless another replay is already running
+func (o *outbox) send(ctx context.Context, session *Session, endpoint string, body []byte) (string, error) {
+	var sessionID string
+	if session != nil {
+		sessionID = session.ID()
+	}
+
+	o.mu.Lock()
+	if err := o.openLocked(); err != nil {
+		o.mu.Unlock()
+		return "", err
+	}
+	seq, err := o.appendLocked(sessionID, endpoint, body)
+	if err != nil {
+		o.mu.Unlock()
+		return "", err
+	}
+	result := make(chan outboxResult, 1)
+	o.waiters[seq] = result
+	o.mu.Unlock()
+
+	for {
+		running := o.replay(ctx, seq)
+		select {
+		case r := <-result:
+			return r.dataID, r.err
+		case <-running:
+			// Another replay ended before reaching our record; take over
+		case <-ctx.Done():
+			o.mu.Lock()
+			delete(o.waiters, seq)
+			o.mu.Unlock()
+			return "", fmt.Errorf("%w: %w", ErrQueued, ctx.Err())
+		}
+	}
+}
+
+// replay delivers queued payloads in order until the target record (0 for all) is
+// settled, one fails or the outbox is empty. When a replay is already running it
+// returns at once with a channel that is closed when that replay ends.
+func (o *outbox) replay(ctx context.Context, target uint64) <-chan struct{} {
+	o.mu.Lock()
+	if o.replaying != nil {
+		running := o.replaying
+		o.mu.Unlock()
+		return running
+	}
+	if err := o.openLocked(); err != nil {
+		for seq := range o.waiters {
+			o.notifyLocked(seq, outboxResult{err: err})
+		}
+		o.mu.Unlock()
+		return nil
+	}
+	done := make(chan struct{})
+	o.replaying = done
+	o.mu.Unlock()
+
+	defer func() {
+		o.mu.Lock()
+		o.replaying = nil
+		o.mu.Unlock()
+		close(done)
+	}()
+
+	o.replayRecords(ctx, target)
+	return nil
+}
+
+// close stops the background replay loop and releases the directory
+func (o *outbox) close(ctx context.Context) error {
+	o.once.Do(func() { close(o.stop) })
+
+	select {
+	case <-o.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	o.mu.Lock()
+	running := o.replaying
+	o.mu.Unlock()
+	if running != nil {
+		select {
+		case <-running:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.unlock != nil {
+		o.unlock()
+		o.unlock = nil
+		o.opened = false
+	}
+	return nil
+}
+
+// run periodically replays queued payloads until stopped
+func (o *outbox) run() {
+	defer close(o.stopped)
+
+	ticker := time.NewTicker(o.config.ReplayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-o.stop:
+			return
+		case <-ticker.C:
+			o.replay(context.Background(), 0)
+		}
+	}
+}
+
+// replayRecords sends queued payloads in order, stopping after target when it is non-zero.
+// Only the running replay calls it, so records are delivered one at a time and in order.
+func (o *outbox) replayRecords(ctx context.Context, target uint64) {
+	for {
+		o.mu.Lock()
+		o.pruneLocked()
+		if len(o.entries) == 0 || (target != 0 && o.entries[0].seq > target) {
+			o.mu.Unlock()
+			return
+		}
+
+		entry := o.entries[0]
+		record, err := o.readLocked(entry)
+		if err != nil {
+			o.notifyLocked(entry.seq, outboxResult{err: err})
+			o.removeHeadLocked()
+			o.mu.Unlock()
+			continue
+		}
+		o.inflight = entry.seq
+		o.mu.Unlock()
+
+		dataID, transient, err := o.deliverRecord(ctx, record)
+
+		o.mu.Lock()
+		o.inflight = 0
+		if transient {
+			// Keep this and every later record for the next replay
+			for seq := range o.waiters {
+				o.notifyLocked(seq, outboxResult{err: fmt.Errorf("%w: %w", ErrQueued, err)})
+			}
+			o.mu.Unlock()
+			return
+		}
+
+		// The intake answered, so the record is settled either way
+		o.notifyLocked(entry.seq, outboxResult{dataID: dataID, err: err})
+		if len(o.entries) > 0 && o.entries[0].seq == entry.seq {
+			o.removeHeadLocked()
+		}
+		o.mu.Unlock()
+	}
+}
+
+// notifyLocked hands a record's outcome to the send waiting for it, if any
+func (o *outbox) notifyLocked(seq uint64, result outboxResult) {
+	if waiter, ok := o.waiters[seq]; ok {
+		waiter <- result
+		delete(o.waiters, seq)
+	}
+}
+
+// deliverRecord sends a queued record to the intake and reports whether a failure is worth replaying
+func (o *outbox) deliverRecord(ctx context.Context, record *outboxRecord) (string, bool, error) {
+	resp, err := o.sdk.post(ctx, o.sdk.lookupSession(record.SessionID), record.Endpoint, record.Body)
+	var apiErr *APIError
+	if err != nil {
+		// A rejection carried by the error, such as a failed session renewal, settles the record
+		return "", !errors.As(err, &apiErr) || apiErr.Retryable(), err
+	}
+
+	dataID, err := decodeDataResponse(record.Endpoint, resp)
+	if errors.As(err, &apiErr) && apiErr.Retryable() {
+		return "", true, err
+	}
+	return dataID, false, err
+}
+
+// openLocked loads queued records from disk on first use
+func (o *outbox) openLocked() error {
+	if o.opened {
+		return nil
+	}
+	if o.config.Dir == "" {
+		return &ConfigError{Field: "OutboxConfig.Dir", Reason: "required when EnableOutbox is set"}
+	}
+
+	if err := os.MkdirAll(o.config.Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	// Another client replaying or numbering records in the same directory would corrupt the queue
+	unlock, err := lockDir(o.config.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to lock outbox directory: %w", err)
+	}
+
+	files, err := os.ReadDir(o.config.Dir)
+	if err != nil {
+		unlock()
+		return fmt.Errorf("failed to read outbox directory: %w", err)
+	}
+
+	o.entries = o.entries[:0]
+	o.size = 0
+	o.nextSeq = 1
+	for _, file := range files {
+		name := file.Name()
+		path := filepath.Join(o.config.Dir, name)
+
+		// Leftovers from interrupted writes
+		if strings.HasSuffix(name, ".tmp") {
+			os.Remove(path)
+			continue
+		}
+
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, ".json"), 10, 64)
+		if err != nil || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		var record outboxRecord
+		if err == nil {
+			err = json.Unmarshal(data, &record)
+		}
+		if err != nil {
+			os.Remove(path)
+			continue
+		}
+
+		o.entries = append(o.entries, outboxEntry{seq: seq, size: int64(len(data)), created: record.Created})
+		o.size += int64(len(data))
+		o.nextSeq = max(o.nextSeq, seq+1)
+	}
+
+	sort.Slice(o.entries, func(i, j int) bool { return o.entries[i].seq < o.entries[j].seq })
+	o.opened = true
+	o.unlock = unlock
+	return nil
+}
+
+// appendLocked writes a new record to disk, applying the size limit and drop policy
+func (o *outbox) appendLocked(sessionID string, endpoint string, body []byte) (uint64, error) {
+	data, err := json.Marshal(outboxRecord{
+		SessionID: sessionID,
+		Endpoint:  endpoint,
+		Body:      body,
+		Created:   time.Now(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal outbox record: %w", err)
+	}
+	size := int64(len(data))
+
+	o.pruneLocked()
+	if o.config.MaxBytes > 0 {
+		if size > o.config.MaxBytes {
+			return 0, ErrOutboxFull
+		}
+		for o.size+size > o.config.MaxBytes {
+			if o.config.DropPolicy == DropNewest {
+				return 0, ErrOutboxFull
+			}
+			o.removeHeadLocked()
+		}
+	}
+
+	seq := o.nextSeq
+	path := o.pathFor(seq)
+	if err := o.writeFile(path, data); err != nil {
+		return 0, err
+	}
+
+	o.nextSeq++
+	o.entries = append(o.entries, outboxEntry{seq: seq, size: size, created: time.Now()})
+	o.size += size
+	return seq, nil
+}
+
+// writeFile atomically writes a record file honoring the durability setting
+func (o *outbox) writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox record: %w", err)
+	}
+
+	_, err = f.Write(data)
+	if err == nil && o.config.Durability != DurabilityNone {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write outbox record: %w", err)
+	}
+
+	if o.config.Durability == DurabilityFull {
+		if dir, err := os.Open(o.config.Dir); err == nil {
+			dir.Sync()
+			dir.Close()
+		}
+	}
+	return nil
+}
+
+// readLocked loads a record from disk
+func (o *outbox) readLocked(entry outboxEntry) (*outboxRecord, error) {
+	data, err := os.ReadFile(o.pathFor(entry.seq))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox record: %w", err)
+	}
+
+	var record outboxRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode outbox record: %w", err)
+	}
+	return &record, nil
+}
+
+// pruneLocked discards records older than the configured maximum age
+func (o *outbox) pruneLocked() {
+	if o.config.MaxAge <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-o.config.MaxAge)
+	for len(o.entries) > 0 && o.entries[0].created.Before(cutoff) {
+		o.removeHeadLocked()
+	}
+}
+
+// removeHeadLocked deletes the oldest record. A send waiting for it learns it was
+// dropped, unless it is being delivered and so still gets the delivery's outcome.
+func (o *outbox) removeHeadLocked() {
+	entry := o.entries[0]
+	os.Remove(o.pathFor(entry.seq))
+	o.entries = o.entries[1:]
+	o.size -= entry.size
+	if entry.seq != o.inflight {
+		o.notifyLocked(entry.seq, outboxResult{err: ErrOutboxFull})
+	}
+}
+
+func (o *outbox) pathFor(seq uint64) string {
+	return filepath.Join(o.config.Dir, fmt.Sprintf("%020d.json", seq))
+}
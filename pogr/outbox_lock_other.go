@@ -0,0 +1,36 @@
+//go:build !unix
+
+package pogr
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// lockedDirs holds the outbox directories in use by clients in this process
+var (
+	lockedDirsMu sync.Mutex
+	lockedDirs   = make(map[string]bool)
+)
+
+// lockDir reserves an outbox directory until release is called. Without flock this
+// only excludes other clients in the same process.
+func lockDir(dir string) (func(), error) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	lockedDirsMu.Lock()
+	defer lockedDirsMu.Unlock()
+
+	if lockedDirs[dir] {
+		return nil, ErrOutboxLocked
+	}
+	lockedDirs[dir] = true
+
+	return func() {
+		lockedDirsMu.Lock()
+		defer lockedDirsMu.Unlock()
+		delete(lockedDirs, dir)
+	}, nil
+}
@@ -0,0 +1,237 @@
+package pogr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+// newOutboxClient creates a client on srv whose outbox lives in dir and only replays on sends
+func newOutboxClient(srv *pogrtest.Server, dir string) pogr.POGRService {
+	config := srv.Config()
+	config.EnableOutbox = true
+	config.OutboxConfig = &pogr.OutboxConfig{
+		Dir:            dir,
+		MaxBytes:       1 << 20,
+		MaxAge:         time.Hour,
+		Durability:     pogr.DurabilityFile,
+		ReplayInterval: time.Hour,
+	}
+	return pogr.NewPOGRSDK(config)
+}
+
+func TestOutboxQueuesAndReplaysInOrder(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	sdk := newOutboxClient(srv, t.TempDir())
+	defer sdk.Close(context.Background())
+
+	srv.FailNext("/data", http.StatusServiceUnavailable, "down")
+	if _, err := sdk.SendData(map[string]int{"seq": 1}, nil); !errors.Is(err, pogr.ErrQueued) {
+		t.Fatalf("SendData during outage: got %v, want ErrQueued", err)
+	}
+
+	if _, err := sdk.SendData(map[string]int{"seq": 2}, nil); err != nil {
+		t.Fatalf("SendData after outage: %v", err)
+	}
+
+	assertDelivered(t, srv, 1, 2)
+}
+
+func TestOutboxReplaysAfterRestart(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+	dir := t.TempDir()
+
+	first := newOutboxClient(srv, dir)
+	srv.FailNext("/data", http.StatusServiceUnavailable, "down")
+	srv.FailNext("/data", http.StatusServiceUnavailable, "down")
+	for seq := 1; seq <= 2; seq++ {
+		if _, err := first.SendData(map[string]int{"seq": seq}, nil); !errors.Is(err, pogr.ErrQueued) {
+			t.Fatalf("SendData %d during outage: got %v, want ErrQueued", seq, err)
+		}
+	}
+	if err := first.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// A new client on the same directory picks up where the first left off
+	second := newOutboxClient(srv, dir)
+	defer second.Close(context.Background())
+
+	if _, err := second.SendData(map[string]int{"seq": 3}, nil); err != nil {
+		t.Fatalf("SendData after restart: %v", err)
+	}
+
+	assertDelivered(t, srv, 1, 2, 3)
+}
+
+// assertDelivered checks that /data accepted payloads with the given seq values in order
+func assertDelivered(t *testing.T, srv *pogrtest.Server, want ...int) {
+	t.Helper()
+
+	var got []int
+	for _, req := range srv.RequestsTo("/data") {
+		if req.Status != http.StatusOK {
+			continue
+		}
+		data, _ := req.Payload["data"].(map[string]interface{})
+		seq, _ := data["seq"].(float64)
+		got = append(got, int(seq))
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("delivered seq %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("delivered seq %v, want %v", got, want)
+		}
+	}
+}
+
+func TestOutboxConfigZeroValuesUseDefaults(t *testing.T) {
+	config := pogr.Config{
+		EnableOutbox: true,
+		OutboxConfig: &pogr.OutboxConfig{Dir: t.TempDir()},
+	}
+	snapshot := config.Snapshot(false).Outbox
+	defaults := pogr.DefaultOutboxConfig()
+
+	if snapshot.MaxBytes != defaults.MaxBytes || snapshot.MaxAge != defaults.MaxAge.String() {
+		t.Errorf("got max_bytes %d and max_age %s, want the defaults", snapshot.MaxBytes, snapshot.MaxAge)
+	}
+	if snapshot.Durability != pogr.DurabilityFile.String() {
+		t.Errorf("got durability %s, want file", snapshot.Durability)
+	}
+}
+
+func TestOutboxDirectoryIsExclusive(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+	dir := t.TempDir()
+
+	first := newOutboxClient(srv, dir)
+	if _, err := first.SendData(map[string]int{"seq": 1}, nil); err != nil {
+		t.Fatalf("SendData on the first client: %v", err)
+	}
+
+	second := newOutboxClient(srv, dir)
+	defer second.Close(context.Background())
+	if _, err := second.SendData(map[string]int{"seq": 2}, nil); !errors.Is(err, pogr.ErrOutboxLocked) {
+		t.Fatalf("SendData on a second client sharing the directory: got %v, want ErrOutboxLocked", err)
+	}
+
+	if err := first.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := second.SendData(map[string]int{"seq": 3}, nil); err != nil {
+		t.Fatalf("SendData once the first client closed: %v", err)
+	}
+
+	assertDelivered(t, srv, 1, 3)
+}
+
+func TestOutboxFollowsRenewedSession(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	sdk := newOutboxClient(srv, t.TempDir())
+	defer sdk.Close(context.Background())
+
+	session, err := sdk.InitWithAssociationID("player-1")
+	if err != nil {
+		t.Fatalf("InitWithAssociationID: %v", err)
+	}
+
+	srv.FailNext("/data", http.StatusServiceUnavailable, "down")
+	srv.FailNext("/data", http.StatusServiceUnavailable, "down")
+	for seq := 1; seq <= 2; seq++ {
+		if _, err := session.SendData(map[string]int{"seq": seq}, nil); !errors.Is(err, pogr.ErrQueued) {
+			t.Fatalf("SendData %d during outage: got %v, want ErrQueued", seq, err)
+		}
+	}
+
+	// Records 1 and 2 were queued under the old ID; the first replay renews the session
+	expired := session.ID()
+	srv.ExpireSession(expired)
+	if _, err := session.SendData(map[string]int{"seq": 3}, nil); err != nil {
+		t.Fatalf("SendData after expiry: %v", err)
+	}
+
+	if session.ID() == expired {
+		t.Fatal("session was not renewed")
+	}
+	assertDelivered(t, srv, 1, 2, 3)
+	for _, req := range srv.RequestsTo("/data") {
+		if req.Status == http.StatusOK && req.SessionID != session.ID() {
+			t.Errorf("payload delivered on session %q, want the renewed %q", req.SessionID, session.ID())
+		}
+	}
+}
+
+func TestOutboxRequiresDir(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	config := srv.Config()
+	config.EnableOutbox = true
+
+	var configErr *pogr.ConfigError
+	if err := config.Validate(); !errors.As(err, &configErr) || configErr.Field != "OutboxConfig.Dir" {
+		t.Errorf("Validate without a Dir: got %v, want a ConfigError for OutboxConfig.Dir", err)
+	}
+
+	sdk := pogr.NewPOGRSDK(config)
+	defer sdk.Close(context.Background())
+	if _, err := sdk.SendData(map[string]int{"seq": 1}, nil); !errors.As(err, &configErr) || configErr.Field != "OutboxConfig.Dir" {
+		t.Errorf("SendData without a Dir: got %v, want a ConfigError for OutboxConfig.Dir", err)
+	}
+	if got := len(srv.RequestsTo("/data")); got != 0 {
+		t.Errorf("got %d requests, want none without an outbox directory", got)
+	}
+}
+
+func TestOutboxSendDoesNotWaitForReplayIO(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	sdk := newOutboxClient(srv, t.TempDir())
+	defer sdk.Close(context.Background())
+
+	// The first send spends 300ms delivering its record
+	srv.SetLatency(300 * time.Millisecond)
+	first := make(chan error, 1)
+	go func() {
+		_, err := sdk.SendData(map[string]int{"seq": 1}, nil)
+		first <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// A concurrent send persists its record right away and gives up waiting at its deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := sdk.SendDataContext(ctx, map[string]int{"seq": 2}, nil); !errors.Is(err, pogr.ErrQueued) {
+		t.Fatalf("SendData during a replay: got %v, want ErrQueued", err)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("SendData returned after %v, want it not to wait for the replay's request", elapsed)
+	}
+
+	if err := <-first; err != nil {
+		t.Fatalf("first SendData: %v", err)
+	}
+	srv.SetLatency(0)
+	if _, err := sdk.SendData(map[string]int{"seq": 3}, nil); err != nil {
+		t.Fatalf("SendData after the replay: %v", err)
+	}
+
+	assertDelivered(t, srv, 1, 2, 3)
+}
@@ -2,11 +2,13 @@ package pogr
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,20 +17,29 @@ var (
 	ErrNoActiveSession = errors.New("no active session")
 	ErrInvalidData     = errors.New("invalid data provided")
 	ErrUnauthorized    = errors.New("unauthorized request")
+	ErrQueueFull       = errors.New("async send queue is full")
+	ErrClosed          = errors.New("sdk is closed")
+	ErrQueued          = errors.New("payload queued in outbox for later delivery")
+	ErrOutboxFull      = errors.New("outbox is full")
+	ErrOutboxLocked    = errors.New("outbox directory is in use by another client")
+	ErrSessionNotFound = errors.New("stored session not found")
 )
 
 // pogrSDK implements the POGRService interface with thread-safety
 type pogrSDK struct {
-	config     Config
-	httpClient HTTPClient
-	mu         sync.RWMutex // Protects session state
-	state      sessionState
-}
-
-// sessionState encapsulates mutable session data
-type sessionState struct {
-	sessionID   string
-	initialized bool
+	config           Config
+	httpClient       HTTPClient
+	customHTTPClient bool
+	limiter          *rateLimiter
+	async            *asyncPipeline
+	outbox           *outbox
+	mu               sync.RWMutex // Protects sessions and renamed
+	sessions         map[*Session]struct{}
+	renamed          map[string]*Session // Session IDs replaced by renewal, for queued payloads still using them
+	closed           atomic.Bool         // Set once Close starts; new sends fail with ErrClosed
+	closeOnce        sync.Once
+	closeDone        chan struct{} // Closed when Close finishes
+	closeErr         error
 }
 
 // NewPOGRSDK creates a new thread-safe instance of the POGR SDK
@@ -37,14 +48,30 @@ func NewPOGRSDK(config Config) POGRService {
 		config.BaseURL = "https://api.pogr.io/v1/intake"
 	}
 
-	if config.HTTPClient == nil {
+	customHTTPClient := config.HTTPClient != nil
+	if !customHTTPClient {
 		config.HTTPClient = NewDefaultHTTPClient(config)
 	}
 
-	return &pogrSDK{
-		config:     config,
-		httpClient: config.HTTPClient,
+	sdk := &pogrSDK{
+		config:           config,
+		httpClient:       chainInterceptors(config.HTTPClient, config.Interceptors),
+		customHTTPClient: customHTTPClient,
+		limiter:          newRateLimiter(config.RateLimits),
+		sessions:         make(map[*Session]struct{}),
+		renamed:          make(map[string]*Session),
+		closeDone:        make(chan struct{}),
 	}
+
+	if config.EnableOutbox {
+		sdk.outbox = newOutbox(sdk, config.OutboxConfig)
+	}
+
+	if config.EnableAsync {
+		sdk.async = newAsyncPipeline(sdk, config.AsyncConfig)
+	}
+
+	return sdk
 }
 
 // NewDefaultHTTPClient creates a default HTTP client with optional connection pooling
@@ -66,12 +93,16 @@ func NewDefaultHTTPClient(config Config) HTTPClient {
 		transport = &http.Transport{}
 	}
 
-	return &defaultHTTPClient{
+	client := &defaultHTTPClient{
 		client: &http.Client{
 			Transport: transport,
 			Timeout:   config.Timeout,
 		},
 	}
+	if config.EnableCompression {
+		client.compression = newCompressionConfig(config.CompressionConfig)
+	}
+	return client
 }
 
 // DefaultPoolConfig returns default connection pool settings
@@ -84,15 +115,85 @@ func DefaultPoolConfig() *ConnectionPoolConfig {
 	}
 }
 
-// setSessionState safely updates session state
-func (sdk *pogrSDK) setSessionState(sessionID string, initialized bool) {
-	sdk.mu.Lock()
-	defer sdk.mu.Unlock()
-	sdk.state.sessionID = sessionID
-	sdk.state.initialized = initialized
+// Flush sends every payload queued by the async pipeline and waits for completion.
+// If ctx ends first, Flush returns its error and the payloads are still sent in the background.
+func (sdk *pogrSDK) Flush(ctx context.Context) error {
+	if sdk.async == nil {
+		return nil
+	}
+	return sdk.async.flush(ctx)
+}
+
+// Close stops accepting new sends, drains pending work, ends every open session and
+// releases idle connections. It is safe to call more than once and from several goroutines;
+// later calls wait for the first to finish. If ctx ends first, Close returns its error and
+// shutdown goes on in the background, so a later Close with a fresh ctx can wait for it.
+func (sdk *pogrSDK) Close(ctx context.Context) error {
+	sdk.closeOnce.Do(func() {
+		sdk.closed.Store(true)
+		go func() {
+			defer close(sdk.closeDone)
+			// Each request is still bounded by Config.Timeout
+			sdk.closeErr = sdk.shutdown(context.WithoutCancel(ctx))
+		}()
+	})
+
+	select {
+	case <-sdk.closeDone:
+		return sdk.closeErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// shutdown performs the work of Close
+func (sdk *pogrSDK) shutdown(ctx context.Context) error {
+	var errs []error
+
+	if sdk.async != nil {
+		if err := sdk.async.close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to drain async pipeline: %w", err))
+		}
+	}
+
+	// Snapshot the sessions so no lock is held while ending them
+	sdk.mu.RLock()
+	sessions := make([]*Session, 0, len(sdk.sessions))
+	for session := range sdk.sessions {
+		sessions = append(sessions, session)
+	}
+	sdk.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	var errMu sync.Mutex
+	for _, session := range sessions {
+		session.StopHeartbeat()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := session.End(ctx); err != nil && !errors.Is(err, ErrNoActiveSession) {
+				errMu.Lock()
+				errs = append(errs, fmt.Errorf("failed to end session %s: %w", session.ID(), err))
+				errMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if sdk.outbox != nil {
+		if err := sdk.outbox.close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop outbox: %w", err))
+		}
+	}
+
+	if client, ok := sdk.config.HTTPClient.(interface{ CloseIdleConnections() }); ok {
+		client.CloseIdleConnections()
+	}
+
+	return errors.Join(errs...)
 }
 
-// PrintConfig returns a string representation of the current configuration
+// PrintConfig returns a string representation of the current configuration with credentials masked
 func (sdk *pogrSDK) PrintConfig() string {
 	return fmt.Sprintf(`
 POGR SDK Configuration:
@@ -102,29 +203,53 @@ BuildKey: %s
 AccessKey: %s
 SecretKey: %s
 Connection Pool Enabled: %v
+Retries Enabled: %v
+Async Enabled: %v
+Outbox Enabled: %v
+Heartbeat Enabled: %v
+Compression Enabled: %v
 Timeout: %v`,
 		sdk.config.BaseURL,
-		sdk.config.ClientKey,
-		sdk.config.BuildKey,
-		sdk.config.AccessKey,
-		sdk.config.SecretKey,
+		maskSecret(sdk.config.ClientKey),
+		maskSecret(sdk.config.BuildKey),
+		maskSecret(sdk.config.AccessKey),
+		maskSecret(sdk.config.SecretKey),
 		sdk.config.EnableConnectionPool,
+		sdk.config.EnableRetries,
+		sdk.config.EnableAsync,
+		sdk.config.EnableOutbox,
+		sdk.config.EnableHeartbeat,
+		sdk.config.EnableCompression,
 		sdk.config.Timeout)
 }
 
 // defaultHTTPClient implements the HTTPClient interface
 type defaultHTTPClient struct {
-	client *http.Client
+	client      *http.Client
+	compression *CompressionConfig // nil when compression is disabled
+}
+
+// CloseIdleConnections closes connections kept alive by the transport
+func (c *defaultHTTPClient) CloseIdleConnections() {
+	c.client.CloseIdleConnections()
 }
 
 func (c *defaultHTTPClient) Do(req *Request) (*Response, error) {
 	var httpReq *http.Request
 	var err error
 
+	reqBody, encoding := req.Body, ""
+	// A caller that set Content-Encoding already encoded the body
+	if headerValue(req.Headers, "Content-Encoding") == "" {
+		if reqBody, encoding, err = compressBody(c.compression, req.Body); err != nil {
+			return nil, err
+		}
+	}
+
 	if req.Context != nil {
-		httpReq, err = http.NewRequestWithContext(req.Context, req.Method, req.URL, bytes.NewBuffer(req.Body))
+		httpReq, err = http.NewRequestWithContext(req.Context, req.Method, req.URL, bytes.NewBuffer(reqBody))
 	} else {
-		httpReq, err = http.NewRequest(req.Method, req.URL, bytes.NewBuffer(req.Body))
+		httpReq, err = http.NewRequest(req.Method, req.URL, bytes.NewBuffer(reqBody))
 	}
 
 	if err != nil {
@@ -134,6 +259,9 @@ func (c *defaultHTTPClient) Do(req *Request) (*Response, error) {
 	for key, value := range req.Headers {
 		httpReq.Header.Set(key, value)
 	}
+	if encoding != "" {
+		httpReq.Header.Set("Content-Encoding", encoding)
+	}
 
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
@@ -0,0 +1,117 @@
+package pogr_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+// newTestClient starts a fake intake and a client pointing at it; configure may adjust the config
+func newTestClient(t *testing.T, configure func(*pogr.Config)) (*pogrtest.Server, pogr.POGRService) {
+	t.Helper()
+
+	srv := pogrtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	config := srv.Config()
+	if configure != nil {
+		configure(&config)
+	}
+	return srv, pogr.NewPOGRSDK(config)
+}
+
+func TestCloseIsIdempotent(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	session, err := sdk.InitWithAssociationID("player-1")
+	if err != nil {
+		t.Fatalf("InitWithAssociationID: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = sdk.Close(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Close call %d: %v", i, err)
+		}
+	}
+	if err := sdk.Close(context.Background()); err != nil {
+		t.Errorf("Close after Close: %v", err)
+	}
+
+	if srv.SessionActive(session.ID()) {
+		t.Errorf("session %s still active after Close", session.ID())
+	}
+	if got := len(srv.RequestsTo("/end")); got != 1 {
+		t.Errorf("got %d /end requests, want 1", got)
+	}
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); !errors.Is(err, pogr.ErrClosed) {
+		t.Errorf("SendData after Close: got %v, want ErrClosed", err)
+	}
+	if _, err := sdk.InitWithAssociationID("player-2"); !errors.Is(err, pogr.ErrClosed) {
+		t.Errorf("Init after Close: got %v, want ErrClosed", err)
+	}
+}
+
+func TestCloseAfterExpiredClose(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	session, err := sdk.InitWithAssociationID("player-1")
+	if err != nil {
+		t.Fatalf("InitWithAssociationID: %v", err)
+	}
+
+	// A signal handler gives up on Close before the intake answers /end
+	srv.SetLatency(200 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := sdk.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close with a short deadline: got %v, want DeadlineExceeded", err)
+	}
+
+	if err := sdk.Close(context.Background()); err != nil {
+		t.Fatalf("Close with a fresh context: %v", err)
+	}
+	if srv.SessionActive(session.ID()) {
+		t.Errorf("session %s still active after Close", session.ID())
+	}
+}
+
+func TestCloseEndsSessionStartedDuringClose(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	// Hold /init open long enough for Close to collect its sessions first
+	srv.SetLatency(100 * time.Millisecond)
+	result := make(chan error, 1)
+	go func() {
+		_, err := sdk.InitWithAssociationID("player-1")
+		result <- err
+	}()
+	time.Sleep(30 * time.Millisecond)
+
+	if err := sdk.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := <-result; !errors.Is(err, pogr.ErrClosed) {
+		t.Fatalf("Init racing Close: got %v, want ErrClosed", err)
+	}
+
+	inits := srv.RequestsTo("/init")
+	if len(inits) != 1 || srv.SessionActive(inits[0].SessionID) {
+		t.Errorf("session issued during Close is still active")
+	}
+}
@@ -0,0 +1,369 @@
+package pogrtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+// redacted replaces secret values in cassettes
+const redacted = "[REDACTED]"
+
+// ErrNoInteraction is returned by a Replayer when no recorded interaction matches a request
+var ErrNoInteraction = errors.New("pogrtest: no matching interaction in cassette")
+
+// secretHeaders are replaced with a placeholder when recording
+var secretHeaders = []string{"ACCESS_KEY", "SECRET_KEY", "POGR_CLIENT", "POGR_BUILD", "Authorization", "Cookie", "Set-Cookie"}
+
+// secretQueryParams are replaced with a placeholder when recording
+var secretQueryParams = []string{"steam_ticket"}
+
+// secretFields are JSON body fields replaced with a placeholder when recording
+var secretFields = []string{"association_id"}
+
+// sessionFields are JSON body fields holding session IDs, mapped to stable placeholders when recording
+var sessionFields = []string{"session_id", "pogr_game_session"}
+
+// Cassette is the on-disk list of recorded interactions
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request/response pair
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is the recorded form of a request
+type CassetteRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// CassetteResponse is the recorded form of a response
+type CassetteResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+}
+
+// Recorder is an HTTPClient that forwards requests and records scrubbed interactions
+type Recorder struct {
+	next pogr.HTTPClient
+	path string
+
+	mu       sync.Mutex
+	cassette Cassette
+	sessions map[string]string // Real session ID to placeholder
+}
+
+// NewRecorder wraps next (the default client if nil) and records to the cassette at path on Save
+func NewRecorder(next pogr.HTTPClient, path string) *Recorder {
+	if next == nil {
+		next = pogr.NewDefaultHTTPClient(pogr.Config{})
+	}
+	return &Recorder{
+		next:     next,
+		path:     path,
+		sessions: make(map[string]string),
+	}
+}
+
+// Do forwards the request and records the interaction
+func (r *Recorder) Do(req *pogr.Request) (*pogr.Response, error) {
+	resp, err := r.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: CassetteRequest{
+			Method:  req.Method,
+			URL:     scrubURL(req.URL),
+			Headers: r.scrubHeaders(req.Headers),
+			Body:    r.scrubBody(req.Body),
+		},
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    r.scrubHeaders(resp.Headers),
+			Body:       r.scrubBody(resp.Body),
+		},
+	})
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the cassette file
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// scrubHeaders redacts credentials and replaces session IDs with placeholders
+func (r *Recorder) scrubHeaders(headers map[string]string) map[string]string {
+	scrubbed := make(map[string]string, len(headers))
+	for key, value := range headers {
+		switch {
+		case strings.EqualFold(key, "INTAKE_SESSION_ID"):
+			scrubbed[key] = r.placeholder(value)
+		case isSecretHeader(key):
+			scrubbed[key] = redacted
+		default:
+			scrubbed[key] = value
+		}
+	}
+	return scrubbed
+}
+
+// scrubBody redacts secret fields and replaces session IDs in a JSON or NDJSON body with placeholders
+func (r *Recorder) scrubBody(body []byte) string {
+	values, ok := decodeBody(body)
+	if !ok {
+		return string(body)
+	}
+
+	var buf bytes.Buffer
+	for i, value := range values {
+		scrubFields(value, r.placeholder)
+		data, err := json.Marshal(value)
+		if err != nil {
+			return string(body)
+		}
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.Write(data)
+	}
+	if len(values) > 1 {
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// decodeBody decodes a JSON body into one value, or an NDJSON body into one value per line
+func decodeBody(body []byte) ([]interface{}, bool) {
+	var values []interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var value interface{}
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			return values, len(values) > 0
+		}
+		if err != nil {
+			return nil, false
+		}
+		values = append(values, value)
+	}
+}
+
+// scrubFields walks a decoded JSON value, redacting secret fields and passing session IDs through session
+func scrubFields(value interface{}, session func(string) string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if text, ok := nested.(string); ok {
+				switch {
+				case containsFold(sessionFields, key):
+					v[key] = session(text)
+				case containsFold(secretFields, key):
+					v[key] = redacted
+				}
+				continue
+			}
+			scrubFields(nested, session)
+		}
+	case []interface{}:
+		for _, nested := range v {
+			scrubFields(nested, session)
+		}
+	}
+}
+
+// placeholder maps a real session ID to a stable placeholder
+func (r *Recorder) placeholder(sessionID string) string {
+	if sessionID == "" {
+		return ""
+	}
+	if placeholder, ok := r.sessions[sessionID]; ok {
+		return placeholder
+	}
+	placeholder := fmt.Sprintf("recorded-session-%d", len(r.sessions)+1)
+	r.sessions[sessionID] = placeholder
+	return placeholder
+}
+
+// Matcher decides whether a recorded request matches an outgoing one
+type Matcher func(req *pogr.Request, recorded CassetteRequest) bool
+
+// MatchMethod matches on the HTTP method
+func MatchMethod(req *pogr.Request, recorded CassetteRequest) bool {
+	return req.Method == recorded.Method
+}
+
+// MatchEndpoint matches on the intake endpoint, ignoring the base URL and query so cassettes work against any intake
+func MatchEndpoint(req *pogr.Request, recorded CassetteRequest) bool {
+	return endpointOf(req.URL) == endpointOf(recorded.URL)
+}
+
+// MatchURL matches on the full scrubbed URL
+func MatchURL(req *pogr.Request, recorded CassetteRequest) bool {
+	return scrubURL(req.URL) == recorded.URL
+}
+
+// MatchBody matches JSON and NDJSON bodies semantically, ignoring scrubbed secrets and
+// session IDs, and other bodies byte for byte
+func MatchBody(req *pogr.Request, recorded CassetteRequest) bool {
+	got, gotOK := decodeBody(req.Body)
+	want, wantOK := decodeBody([]byte(recorded.Body))
+	if gotOK && wantOK {
+		ignore := func(string) string { return redacted }
+		for _, value := range append(got, want...) {
+			scrubFields(value, ignore)
+		}
+		return reflect.DeepEqual(got, want)
+	}
+	return bytes.Equal(req.Body, []byte(recorded.Body))
+}
+
+// Replayer is an HTTPClient that serves responses from a cassette without network access
+type Replayer struct {
+	matchers []Matcher
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer loads the cassette at path. Each interaction is served once, in
+// recorded order, to the first request satisfying every matcher; without
+// matchers MatchMethod and MatchEndpoint are used.
+func NewReplayer(path string, matchers ...Matcher) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette: %w", err)
+	}
+
+	if len(matchers) == 0 {
+		matchers = []Matcher{MatchMethod, MatchEndpoint}
+	}
+
+	return &Replayer{
+		matchers:     matchers,
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}, nil
+}
+
+// Do serves the next matching recorded response
+func (r *Replayer) Do(req *pogr.Request) (*pogr.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || !r.matches(req, interaction.Request) {
+			continue
+		}
+		r.used[i] = true
+
+		return &pogr.Response{
+			StatusCode: interaction.Response.StatusCode,
+			Body:       []byte(interaction.Response.Body),
+			Headers:    interaction.Response.Headers,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, scrubURL(req.URL))
+}
+
+// Remaining returns how many recorded interactions have not been served
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remaining := 0
+	for _, used := range r.used {
+		if !used {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+func (r *Replayer) matches(req *pogr.Request, recorded CassetteRequest) bool {
+	for _, match := range r.matchers {
+		if !match(req, recorded) {
+			return false
+		}
+	}
+	return true
+}
+
+func isSecretHeader(key string) bool {
+	return containsFold(secretHeaders, key)
+}
+
+// containsFold reports whether names holds key, ignoring case
+func containsFold(names []string, key string) bool {
+	for _, name := range names {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrubURL redacts secret query parameters
+func scrubURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	query := u.Query()
+	for _, param := range secretQueryParams {
+		if query.Has(param) {
+			query.Set(param, redacted)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// endpointOf returns the intake endpoint (e.g. "/data") a URL targets
+func endpointOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return "/" + path.Base(u.Path)
+}
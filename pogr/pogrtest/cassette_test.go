@@ -0,0 +1,140 @@
+package pogrtest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+// record runs a short session against a fake intake through a Recorder and saves the cassette
+func record(t *testing.T, path string) (sessionID string) {
+	t.Helper()
+
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	recorder := pogrtest.NewRecorder(nil, path)
+	config := srv.Config()
+	config.HTTPClient = recorder
+	sdk := pogr.NewPOGRSDK(config)
+	defer sdk.Close(context.Background())
+
+	session, err := sdk.InitWithAssociationID("secret-association")
+	if err != nil {
+		t.Fatalf("InitWithAssociationID: %v", err)
+	}
+	tags := &pogr.Tags{AssociationID: "secret-association", PogrGameSession: session.ID()}
+	if _, err := session.SendData(map[string]int{"score": 7}, tags); err != nil {
+		t.Fatalf("SendData: %v", err)
+	}
+
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return session.ID()
+}
+
+func TestRecorderScrubsSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	sessionID := record(t, path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("cassette written with mode %o, want 600", perm)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	cassette := string(data)
+	for _, secret := range []string{"secret-association", `"` + sessionID + `"`, pogrtest.ClientKey, pogrtest.SecretKey} {
+		if strings.Contains(cassette, secret) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+	if !strings.Contains(cassette, "recorded-session-1") {
+		t.Error("cassette has no session placeholder")
+	}
+}
+
+func TestRecorderScrubsNDJSON(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := pogrtest.NewRecorder(nil, path)
+	config := srv.Config()
+	config.HTTPClient = recorder
+	config.EnableBulkRequests = true
+	config.BatchConfig = &pogr.BatchConfig{Format: pogr.BatchNDJSON}
+	sdk := pogr.NewPOGRSDK(config)
+	defer sdk.Close(context.Background())
+
+	tags := &pogr.Tags{AssociationID: "secret-association"}
+	batch := []pogr.DataPayload{{Data: 1, Tags: tags}, {Data: 2, Tags: tags}}
+	if _, err := sdk.SendDataBatch(context.Background(), batch); err != nil {
+		t.Fatalf("SendDataBatch: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if strings.Contains(string(data), "secret-association") {
+		t.Error("cassette contains the association ID from an NDJSON body")
+	}
+
+	replayer, err := pogrtest.NewReplayer(path, pogrtest.MatchEndpoint, pogrtest.MatchBody)
+	if err != nil {
+		t.Fatalf("NewReplayer: %v", err)
+	}
+	config.HTTPClient = replayer
+	replayed := pogr.NewPOGRSDK(config)
+	defer replayed.Close(context.Background())
+	if _, err := replayed.SendDataBatch(context.Background(), batch); err != nil {
+		t.Errorf("SendDataBatch against the replayed NDJSON body: %v", err)
+	}
+}
+
+func TestReplayerServesRecording(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	record(t, path)
+
+	replayer, err := pogrtest.NewReplayer(path, pogrtest.MatchMethod, pogrtest.MatchEndpoint, pogrtest.MatchBody)
+	if err != nil {
+		t.Fatalf("NewReplayer: %v", err)
+	}
+	sdk := pogr.NewPOGRSDK(pogr.Config{
+		ClientKey:  "other-client",
+		BuildKey:   "other-build",
+		BaseURL:    "http://intake.invalid",
+		HTTPClient: replayer,
+	})
+
+	session, err := sdk.InitWithAssociationID("another-association")
+	if err != nil {
+		t.Fatalf("InitWithAssociationID: %v", err)
+	}
+	if session.ID() != "recorded-session-1" {
+		t.Errorf("got session %q, want the recorded placeholder", session.ID())
+	}
+	tags := &pogr.Tags{AssociationID: "another-association", PogrGameSession: session.ID()}
+	if _, err := session.SendData(map[string]int{"score": 7}, tags); err != nil {
+		t.Fatalf("SendData: %v", err)
+	}
+	if _, err := session.SendData(map[string]int{"score": 8}, tags); err == nil {
+		t.Error("SendData with a different body matched a recorded interaction")
+	}
+}
@@ -0,0 +1,390 @@
+// Package pogrtest provides an in-process fake POGR intake for tests.
+package pogrtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+// Credentials accepted by a Server created with NewServer
+const (
+	ClientKey = "test-client-key"
+	BuildKey  = "test-build-key"
+	AccessKey = "test-access-key"
+	SecretKey = "test-secret-key"
+)
+
+// RecordedRequest is a request received by the fake intake
+type RecordedRequest struct {
+	Method    string
+	Endpoint  string // e.g. "/data"
+	Query     url.Values
+	Headers   http.Header
+	Body      []byte                   // Decompressed when sent with Content-Encoding: gzip
+	Payload   map[string]interface{}   // Decoded JSON body, nil if the body is not a JSON object
+	Items     []map[string]interface{} // Decoded items of an experimental bulk request, see pogr.BatchConfig
+	SessionID string                   // Session the request authenticated with, if any
+	Status    int                      // Status code the fake intake answered with
+	Time      time.Time
+}
+
+// Fault scripts a single response for an endpoint
+type Fault struct {
+	Status     int           // Status code to answer with
+	Message    string        // Error message placed in the JSON body
+	Body       string        // Raw body sent instead of JSON, e.g. an HTML error page
+	RetryAfter time.Duration // Sets the Retry-After header when non-zero
+	Latency    time.Duration // Delay before answering
+}
+
+// Server is a fake intake that validates auth headers, issues sessions and
+// records every request for assertions
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []RecordedRequest
+	sessions map[string]bool
+	faults   map[string][]Fault
+	latency  time.Duration
+	nextID   int
+}
+
+// NewServer starts a fake intake; callers must Close it when done
+func NewServer() *Server {
+	s := &Server{
+		sessions: make(map[string]bool),
+		faults:   make(map[string][]Fault),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Config returns an SDK configuration pointing at the fake intake with valid credentials
+func (s *Server) Config() pogr.Config {
+	return pogr.Config{
+		ClientKey: ClientKey,
+		BuildKey:  BuildKey,
+		AccessKey: AccessKey,
+		SecretKey: SecretKey,
+		BaseURL:   s.URL,
+	}
+}
+
+// Requests returns every request received so far
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// RequestsTo returns the requests received by an endpoint, e.g. "/event"
+func (s *Server) RequestsTo(endpoint string) []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []RecordedRequest
+	for _, req := range s.requests {
+		if req.Endpoint == endpoint {
+			matched = append(matched, req)
+		}
+	}
+	return matched
+}
+
+// Reset forgets recorded requests, sessions and pending faults
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.sessions = make(map[string]bool)
+	s.faults = make(map[string][]Fault)
+	s.latency = 0
+}
+
+// SessionActive reports whether a session was issued and not yet ended or expired
+func (s *Server) SessionActive(sessionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[sessionID]
+}
+
+// ExpireSession invalidates a session so later requests using it are rejected
+func (s *Server) ExpireSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+}
+
+// SetLatency delays every response by d, or until the client cancels the request
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// InjectFault queues scripted responses for the next requests to an endpoint
+func (s *Server) InjectFault(endpoint string, faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[endpoint] = append(s.faults[endpoint], faults...)
+}
+
+// FailNext makes the next request to an endpoint fail with the given status and message
+func (s *Server) FailNext(endpoint string, status int, message string) {
+	s.InjectFault(endpoint, Fault{Status: status, Message: message})
+}
+
+// RateLimitNext makes the next request to an endpoint answer 429 with a Retry-After header
+func (s *Server) RateLimitNext(endpoint string, retryAfter time.Duration) {
+	s.InjectFault(endpoint, Fault{
+		Status:     http.StatusTooManyRequests,
+		Message:    "rate limit exceeded",
+		RetryAfter: retryAfter,
+	})
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		json.NewEncoder(w).Encode(failure(err.Error()))
+		return
+	}
+
+	record := RecordedRequest{
+		Method:   r.Method,
+		Endpoint: endpointOf(r.URL.Path),
+		Query:    r.URL.Query(),
+		Headers:  r.Header.Clone(),
+		Body:     body,
+		Time:     time.Now(),
+	}
+	json.Unmarshal(body, &record.Payload)
+
+	fault, latency := s.nextFault(record.Endpoint)
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			// The client gave up; there is nobody left to answer
+			return
+		}
+	}
+
+	if fault != nil {
+		record.Status = fault.Status
+		s.record(record)
+		writeFault(w, fault)
+		return
+	}
+
+	status, response := s.respond(r, &record)
+	record.Status = status
+	s.record(record)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// readBody reads a request body, decoding gzip when the client compressed it
+func readBody(r *http.Request) ([]byte, error) {
+	switch encoding := r.Header.Get("Content-Encoding"); encoding {
+	case "":
+		return io.ReadAll(r.Body)
+	case "gzip":
+		reader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+}
+
+// decodeBatch decodes a JSON array or NDJSON body and reports whether the body is a batch.
+// This is the SDK's experimental bulk format, not part of the documented intake API.
+func decodeBatch(r *http.Request, body []byte) ([]map[string]interface{}, bool, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-ndjson") {
+		var items []map[string]interface{}
+		for _, line := range bytes.Split(body, []byte("\n")) {
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			var item map[string]interface{}
+			if err := json.Unmarshal(line, &item); err != nil {
+				return nil, true, fmt.Errorf("invalid ndjson line: %w", err)
+			}
+			items = append(items, item)
+		}
+		return items, true, nil
+	}
+
+	if trimmed := bytes.TrimSpace(body); len(trimmed) == 0 || trimmed[0] != '[' {
+		return nil, false, nil
+	}
+	var items []map[string]interface{}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, true, fmt.Errorf("invalid json array: %w", err)
+	}
+	return items, true, nil
+}
+
+// nextFault pops the next scripted fault for an endpoint and returns the latency to apply
+func (s *Server) nextFault(endpoint string) (*Fault, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queue := s.faults[endpoint]
+	if len(queue) == 0 {
+		return nil, s.latency
+	}
+	fault := queue[0]
+	s.faults[endpoint] = queue[1:]
+	return &fault, s.latency + fault.Latency
+}
+
+// respond validates the request and produces the intake's answer
+func (s *Server) respond(r *http.Request, record *RecordedRequest) (int, map[string]interface{}) {
+	if r.Method != http.MethodPost {
+		return http.StatusMethodNotAllowed, failure("method not allowed")
+	}
+
+	switch record.Endpoint {
+	case "/init":
+		return s.init(r, record)
+	case "/end":
+		sessionID := r.Header.Get("INTAKE_SESSION_ID")
+		if !s.SessionActive(sessionID) {
+			return http.StatusUnauthorized, failure("session expired or invalid")
+		}
+		s.ExpireSession(sessionID)
+		record.SessionID = sessionID
+		return http.StatusOK, map[string]interface{}{"success": true}
+	case "/data", "/event", "/logs", "/metrics", "/monitor":
+		if status, message := s.authenticate(r, record); status != http.StatusOK {
+			return status, failure(message)
+		}
+		if items, batch, err := decodeBatch(r, record.Body); batch {
+			if err != nil {
+				return http.StatusBadRequest, failure(err.Error())
+			}
+			record.Items = items
+			results := make([]map[string]string, len(items))
+			for i := range items {
+				results[i] = map[string]string{"data_id": s.newID("data")}
+			}
+			return http.StatusOK, map[string]interface{}{
+				"success": true,
+				"payload": map[string]interface{}{"results": results},
+			}
+		}
+		return http.StatusOK, map[string]interface{}{
+			"success": true,
+			"payload": map[string]string{"data_id": s.newID("data")},
+		}
+	default:
+		return http.StatusNotFound, failure("unknown endpoint")
+	}
+}
+
+// init issues a session for a client/build key pair plus a JWT, association ID or Steam ticket
+func (s *Server) init(r *http.Request, record *RecordedRequest) (int, map[string]interface{}) {
+	if r.Header.Get("POGR_CLIENT") != ClientKey || r.Header.Get("POGR_BUILD") != BuildKey {
+		return http.StatusUnauthorized, failure("invalid client or build key")
+	}
+
+	hasJWT := strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") && len(r.Header.Get("Authorization")) > len("Bearer ")
+	associationID, _ := record.Payload["association_id"].(string)
+	hasAssociation := associationID != ""
+	hasTicket := r.URL.Query().Get("steam_ticket") != ""
+	if !hasJWT && !hasAssociation && !hasTicket {
+		return http.StatusBadRequest, failure("missing jwt, association_id or steam_ticket")
+	}
+
+	sessionID := s.newID("session")
+	s.mu.Lock()
+	s.sessions[sessionID] = true
+	s.mu.Unlock()
+
+	record.SessionID = sessionID
+	return http.StatusOK, map[string]interface{}{
+		"success": true,
+		"payload": map[string]string{"session_id": sessionID},
+	}
+}
+
+// authenticate accepts a live session, an access/secret key pair or a client/build key pair
+func (s *Server) authenticate(r *http.Request, record *RecordedRequest) (int, string) {
+	if sessionID := r.Header.Get("INTAKE_SESSION_ID"); sessionID != "" {
+		if !s.SessionActive(sessionID) {
+			return http.StatusUnauthorized, "session expired or invalid"
+		}
+		record.SessionID = sessionID
+		return http.StatusOK, ""
+	}
+
+	if r.Header.Get("ACCESS_KEY") != "" || r.Header.Get("SECRET_KEY") != "" {
+		if r.Header.Get("ACCESS_KEY") != AccessKey || r.Header.Get("SECRET_KEY") != SecretKey {
+			return http.StatusUnauthorized, "invalid access or secret key"
+		}
+		return http.StatusOK, ""
+	}
+
+	if r.Header.Get("POGR_CLIENT") == ClientKey && r.Header.Get("POGR_BUILD") == BuildKey {
+		return http.StatusOK, ""
+	}
+	return http.StatusUnauthorized, "missing credentials"
+}
+
+func (s *Server) record(record RecordedRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, record)
+}
+
+func (s *Server) newID(prefix string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	return fmt.Sprintf("%s-%d", prefix, s.nextID)
+}
+
+func writeFault(w http.ResponseWriter, fault *Fault) {
+	if fault.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(fault.RetryAfter.Seconds()))))
+	}
+
+	if fault.Body != "" {
+		w.WriteHeader(fault.Status)
+		io.WriteString(w, fault.Body)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(fault.Status)
+	json.NewEncoder(w).Encode(failure(fault.Message))
+}
+
+func failure(message string) map[string]interface{} {
+	return map[string]interface{}{"success": false, "error": message}
+}
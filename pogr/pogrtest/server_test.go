@@ -0,0 +1,243 @@
+package pogrtest_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+// post sends a raw request to the fake intake
+func post(t *testing.T, srv *pogrtest.Server, endpoint string, headers map[string]string, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+endpoint, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", endpoint, err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestServerValidatesCredentials(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"access keys", map[string]string{"ACCESS_KEY": pogrtest.AccessKey, "SECRET_KEY": pogrtest.SecretKey}, http.StatusOK},
+		{"client keys", map[string]string{"POGR_CLIENT": pogrtest.ClientKey, "POGR_BUILD": pogrtest.BuildKey}, http.StatusOK},
+		{"wrong secret", map[string]string{"ACCESS_KEY": pogrtest.AccessKey, "SECRET_KEY": "wrong"}, http.StatusUnauthorized},
+		{"unknown session", map[string]string{"INTAKE_SESSION_ID": "session-404"}, http.StatusUnauthorized},
+		{"no credentials", nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := post(t, srv, "/data", tt.headers, `{"data":{}}`); resp.StatusCode != tt.want {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerIssuesAndEndsSessions(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	keys := map[string]string{"POGR_CLIENT": pogrtest.ClientKey, "POGR_BUILD": pogrtest.BuildKey}
+	if resp := post(t, srv, "/init", keys, `{"association_id":"player-1"}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("/init answered %d", resp.StatusCode)
+	}
+
+	sessionID := srv.RequestsTo("/init")[0].SessionID
+	if !srv.SessionActive(sessionID) {
+		t.Fatalf("session %q not active after /init", sessionID)
+	}
+
+	session := map[string]string{"INTAKE_SESSION_ID": sessionID}
+	if resp := post(t, srv, "/event", session, `{"event":"match"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("/event on the session answered %d", resp.StatusCode)
+	}
+	if got := srv.RequestsTo("/event")[0].SessionID; got != sessionID {
+		t.Errorf("recorded session %q, want %q", got, sessionID)
+	}
+
+	if resp := post(t, srv, "/end", session, ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("/end answered %d", resp.StatusCode)
+	}
+	if srv.SessionActive(sessionID) {
+		t.Error("session still active after /end")
+	}
+	if resp := post(t, srv, "/event", session, `{"event":"match"}`); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("/event on an ended session answered %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestServerRejectsInitWithoutCredential(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	keys := map[string]string{"POGR_CLIENT": pogrtest.ClientKey, "POGR_BUILD": pogrtest.BuildKey}
+	if resp := post(t, srv, "/init", keys, ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("/init without a credential answered %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestServerFailNext(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	keys := map[string]string{"ACCESS_KEY": pogrtest.AccessKey, "SECRET_KEY": pogrtest.SecretKey}
+	srv.FailNext("/event", http.StatusServiceUnavailable, "intake down")
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/event", bytes.NewBufferString("{}"))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	for key, value := range keys {
+		req.Header.Set(key, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /event: %v", err)
+	}
+	var body struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("decode failure body: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || body.Success || body.Error != "intake down" {
+		t.Errorf("got status %d with body %+v, want 503 with the scripted message", resp.StatusCode, body)
+	}
+
+	// The fault only applies to the endpoint it was scripted for, and only once
+	if resp := post(t, srv, "/data", keys, "{}"); resp.StatusCode != http.StatusOK {
+		t.Errorf("/data answered %d, want 200", resp.StatusCode)
+	}
+	if resp := post(t, srv, "/event", keys, "{}"); resp.StatusCode != http.StatusOK {
+		t.Errorf("second /event answered %d, want 200", resp.StatusCode)
+	}
+
+	requests := srv.RequestsTo("/event")
+	if len(requests) != 2 || requests[0].Status != http.StatusServiceUnavailable || requests[1].Status != http.StatusOK {
+		t.Errorf("recorded %d /event requests, want the 503 then a 200", len(requests))
+	}
+}
+
+func TestServerExpireSession(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	keys := map[string]string{"POGR_CLIENT": pogrtest.ClientKey, "POGR_BUILD": pogrtest.BuildKey}
+	post(t, srv, "/init", keys, `{"association_id":"player-1"}`)
+	post(t, srv, "/init", keys, `{"association_id":"player-2"}`)
+	inits := srv.RequestsTo("/init")
+	expired, kept := inits[0].SessionID, inits[1].SessionID
+
+	srv.ExpireSession(expired)
+
+	if srv.SessionActive(expired) {
+		t.Errorf("session %s still active after ExpireSession", expired)
+	}
+	if resp := post(t, srv, "/data", map[string]string{"INTAKE_SESSION_ID": expired}, "{}"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("/data on the expired session answered %d, want 401", resp.StatusCode)
+	}
+	if resp := post(t, srv, "/end", map[string]string{"INTAKE_SESSION_ID": expired}, ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("/end on the expired session answered %d, want 401", resp.StatusCode)
+	}
+	if resp := post(t, srv, "/data", map[string]string{"INTAKE_SESSION_ID": kept}, "{}"); resp.StatusCode != http.StatusOK {
+		t.Errorf("/data on the other session answered %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestServerScriptedFaults(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	keys := map[string]string{"ACCESS_KEY": pogrtest.AccessKey, "SECRET_KEY": pogrtest.SecretKey}
+	srv.RateLimitNext("/logs", 2*time.Second)
+	srv.InjectFault("/logs", pogrtest.Fault{Status: http.StatusBadGateway, Body: "<html>bad gateway</html>", Latency: 50 * time.Millisecond})
+
+	resp := post(t, srv, "/logs", keys, "{}")
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "2" {
+		t.Errorf("got status %d with Retry-After %q, want 429 with 2", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+
+	start := time.Now()
+	resp = post(t, srv, "/logs", keys, "{}")
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("got status %d, want 502", resp.StatusCode)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("fault answered after %v, want at least its 50ms latency", elapsed)
+	}
+
+	if resp = post(t, srv, "/logs", keys, "{}"); resp.StatusCode != http.StatusOK {
+		t.Errorf("got status %d after the faults ran out, want 200", resp.StatusCode)
+	}
+
+	requests := srv.RequestsTo("/logs")
+	if len(requests) != 3 || requests[0].Status != http.StatusTooManyRequests || requests[2].Status != http.StatusOK {
+		t.Errorf("recorded %d requests, want the 429, 502 and 200", len(requests))
+	}
+}
+
+func TestServerReset(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	srv.FailNext("/data", http.StatusInternalServerError, "boom")
+	post(t, srv, "/data", nil, "{}")
+	srv.Reset()
+
+	if got := len(srv.Requests()); got != 0 {
+		t.Errorf("got %d requests after Reset, want 0", got)
+	}
+	keys := map[string]string{"ACCESS_KEY": pogrtest.AccessKey, "SECRET_KEY": pogrtest.SecretKey}
+	if resp := post(t, srv, "/data", keys, "{}"); resp.StatusCode != http.StatusOK {
+		t.Errorf("got status %d after Reset, want 200", resp.StatusCode)
+	}
+}
+
+func TestServerDecodesGzipBodies(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	io.WriteString(w, `{"data":{"score":1}}`)
+	w.Close()
+
+	headers := map[string]string{"ACCESS_KEY": pogrtest.AccessKey, "SECRET_KEY": pogrtest.SecretKey, "Content-Encoding": "gzip"}
+	if resp := post(t, srv, "/data", headers, compressed.String()); resp.StatusCode != http.StatusOK {
+		t.Fatalf("gzip body answered %d, want 200", resp.StatusCode)
+	}
+	if got := string(srv.RequestsTo("/data")[0].Body); got != `{"data":{"score":1}}` {
+		t.Errorf("recorded body %q, want it decompressed", got)
+	}
+
+	headers["Content-Encoding"] = "br"
+	if resp := post(t, srv, "/data", headers, "{}"); resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("brotli body answered %d, want 415", resp.StatusCode)
+	}
+}
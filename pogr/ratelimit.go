@@ -0,0 +1,212 @@
+package pogr
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit configures a client-side token bucket for a single endpoint
+type RateLimit struct {
+	RequestsPerSecond float64 // Sustained request rate
+	Burst             int     // Maximum requests allowed at once
+}
+
+// tokenBucket is a minimal token bucket limiter
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(max(limit.Burst, 1))
+	return &tokenBucket{
+		rate:   limit.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// take consumes a token if one is available, otherwise it returns how long to wait
+func (b *tokenBucket) take() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// rateLimiter throttles requests per endpoint and tracks server-imposed pauses
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	paused  map[string]serverPause
+}
+
+// serverPause is a pause requested by the intake and the response that requested it
+type serverPause struct {
+	until time.Time
+	resp  *Response
+}
+
+func newRateLimiter(limits map[string]RateLimit) *rateLimiter {
+	buckets := make(map[string]*tokenBucket)
+	for endpoint, limit := range limits {
+		if limit.RequestsPerSecond > 0 {
+			buckets[endpoint] = newTokenBucket(limit)
+		}
+	}
+
+	return &rateLimiter{
+		buckets: buckets,
+		paused:  make(map[string]serverPause),
+	}
+}
+
+// pause blocks the endpoint until the given time; resp is the response that asked for it
+func (l *rateLimiter) pause(endpoint string, until time.Time, resp *Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until.After(l.paused[endpoint].until) {
+		l.paused[endpoint] = serverPause{until: until, resp: resp}
+	}
+}
+
+// pausedFor returns the remaining pause for an endpoint and the response that imposed it
+func (l *rateLimiter) pausedFor(endpoint string) (time.Duration, *Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pause, ok := l.paused[endpoint]
+	if !ok {
+		return 0, nil
+	}
+	remaining := time.Until(pause.until)
+	if remaining <= 0 {
+		delete(l.paused, endpoint)
+		return 0, nil
+	}
+	return remaining, pause.resp
+}
+
+// wait blocks until the endpoint may be called or the context is done. A server pause
+// longer than maxPause (negative for no limit) or outlasting the context deadline is not waited
+// out: wait returns the throttled response instead so the caller fails fast with its error.
+func (l *rateLimiter) wait(ctx context.Context, endpoint string, maxPause time.Duration) (*Response, error) {
+	for {
+		delay, throttled := l.pausedFor(endpoint)
+		if delay > 0 {
+			deadline, ok := ctx.Deadline()
+			if (maxPause >= 0 && delay > maxPause) || (ok && time.Until(deadline) < delay) {
+				return pendingResponse(throttled, delay), nil
+			}
+		} else {
+			bucket, ok := l.buckets[endpoint]
+			if !ok {
+				return nil, nil
+			}
+			if delay = bucket.take(); delay == 0 {
+				return nil, nil
+			}
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// pendingResponse copies a throttled response with Retry-After set to the remaining pause
+func pendingResponse(resp *Response, remaining time.Duration) *Response {
+	headers := make(map[string]string, len(resp.Headers)+1)
+	for key, value := range resp.Headers {
+		if !strings.EqualFold(key, "Retry-After") {
+			headers[key] = value
+		}
+	}
+	headers["Retry-After"] = strconv.Itoa(int(math.Ceil(remaining.Seconds())))
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Body:       resp.Body,
+		Headers:    headers,
+	}
+}
+
+// sleepContext waits for the given duration or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryAfter extracts the server-requested delay from a rate limited response
+func retryAfter(resp *Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+	return parseRetryAfter(headerValue(resp.Headers, "Retry-After"), time.Now())
+}
+
+// parseRetryAfter parses a Retry-After value given in seconds or as an HTTP-date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// headerValue looks up a header case-insensitively
+func headerValue(headers map[string]string, name string) string {
+	if value, ok := headers[name]; ok {
+		return value
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// endpointOf returns the intake endpoint (e.g. "/data") a request URL targets
+func endpointOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return "/" + path.Base(u.Path)
+}
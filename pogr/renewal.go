@@ -0,0 +1,23 @@
+package pogr
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// SessionRenewFunc re-establishes an expired session, typically by calling one of the
+// Init* methods with fresh credentials. The expired handle adopts the returned session.
+type SessionRenewFunc func(ctx context.Context, svc POGRService, expired *Session) (*Session, error)
+
+// isSessionExpired reports whether the intake rejected a request's session
+func isSessionExpired(resp *Response) bool {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	if resp.StatusCode == http.StatusOK {
+		return false
+	}
+	message := strings.ToLower(errorMessage(resp))
+	return strings.Contains(message, "session") && (strings.Contains(message, "expired") || strings.Contains(message, "invalid"))
+}
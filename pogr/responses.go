@@ -2,7 +2,6 @@ package pogr
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 )
@@ -31,46 +30,42 @@ type genericResponse struct {
 
 // handleInitResponse processes initialization responses
 func (sdk *pogrSDK) handleInitResponse(req *Request) (string, error) {
-	resp, err := sdk.httpClient.Do(req)
+	resp, err := sdk.do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to execute request: %w", err)
 	}
 
 	var initResp initResponse
-	if err := json.Unmarshal(resp.Body, &initResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(endpointOf(req.URL), resp, &initResp); err != nil {
+		return "", err
 	}
 
 	if !initResp.Success {
-		return "", errors.New(initResp.Error)
+		return "", newAPIError(endpointOf(req.URL), resp, initResp.Error)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	sdk.setSessionState(initResp.Payload.SessionID, true)
 	return initResp.Payload.SessionID, nil
 }
 
 // handleDataResponse processes data submission responses
 func (sdk *pogrSDK) handleDataResponse(req *Request) (string, error) {
-	resp, err := sdk.httpClient.Do(req)
+	resp, err := sdk.do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to execute request: %w", err)
 	}
 
+	return decodeDataResponse(endpointOf(req.URL), resp)
+}
+
+// decodeDataResponse extracts the data ID from a data submission response
+func decodeDataResponse(endpoint string, resp *Response) (string, error) {
 	var dataResp dataResponse
-	if err := json.Unmarshal(resp.Body, &dataResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(endpoint, resp, &dataResp); err != nil {
+		return "", err
 	}
 
 	if !dataResp.Success {
-		return "", errors.New(dataResp.Error)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return "", newAPIError(endpoint, resp, dataResp.Error)
 	}
 
 	return dataResp.Payload.DataID, nil
@@ -78,23 +73,31 @@ func (sdk *pogrSDK) handleDataResponse(req *Request) (string, error) {
 
 // handleGenericResponse processes general responses
 func (sdk *pogrSDK) handleGenericResponse(req *Request) error {
-	resp, err := sdk.httpClient.Do(req)
+	resp, err := sdk.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
 
 	var genResp genericResponse
-	if err := json.Unmarshal(resp.Body, &genResp); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(endpointOf(req.URL), resp, &genResp); err != nil {
+		return err
 	}
 
 	if !genResp.Success {
-		return errors.New(genResp.Error)
+		return newAPIError(endpointOf(req.URL), resp, genResp.Error)
 	}
 
+	return nil
+}
+
+// decodeResponse checks the status code and decodes a successful JSON response into v
+func decodeResponse(endpoint string, resp *Response, v interface{}) error {
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return newAPIError(endpoint, resp, "")
 	}
 
+	if err := json.Unmarshal(resp.Body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
 	return nil
 }
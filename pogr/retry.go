@@ -0,0 +1,148 @@
+package pogr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how failed intake requests are retried
+type RetryPolicy struct {
+	MaxAttempts          int           // Total attempts including the first one
+	BaseDelay            time.Duration // Delay before the first retry
+	MaxDelay             time.Duration // Upper bound for any single delay, including a server's Retry-After
+	Jitter               float64       // Fraction of each delay that is randomized (0-1)
+	RetryableStatusCodes []int         // HTTP status codes that trigger a retry
+	RetryNetworkErrors   bool          // Retry when the request could not be sent
+	RetryTimeouts        bool          // Retry when a single attempt timed out
+}
+
+// DefaultRetryPolicy returns default retry settings
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryNetworkErrors: true,
+		RetryTimeouts:      true,
+	}
+}
+
+// shouldRetry reports whether an attempt outcome is retryable under the policy
+func (p *RetryPolicy) shouldRetry(resp *Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return false
+		}
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return p.RetryTimeouts
+		}
+		return p.RetryNetworkErrors
+	}
+
+	for _, code := range p.RetryableStatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay to wait after the given failed attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 && delay > 0 {
+		jitter := min(p.Jitter, 1)
+		spread := float64(delay) * jitter
+		delay = time.Duration(float64(delay) - spread + rand.Float64()*spread)
+	}
+	return delay
+}
+
+// retryPolicy returns the active retry policy, or nil when retries are disabled
+func (sdk *pogrSDK) retryPolicy() *RetryPolicy {
+	if !sdk.config.EnableRetries {
+		return nil
+	}
+	if sdk.config.RetryPolicy == nil {
+		return DefaultRetryPolicy()
+	}
+	return sdk.config.RetryPolicy
+}
+
+// do executes a request, honoring rate limits and retrying transient failures according to the retry policy.
+// While the intake has paused the endpoint for longer than the call can wait, do answers with
+// the throttled response without sending the request. Without retries no pause is waited out.
+func (sdk *pogrSDK) do(req *Request) (*Response, error) {
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	endpoint := endpointOf(req.URL)
+	policy := sdk.retryPolicy()
+
+	// Server pauses are waited out up to MaxDelay, and not at all when retries are off
+	var maxPause time.Duration
+	if policy != nil {
+		maxPause = policy.MaxDelay
+		if maxPause <= 0 {
+			maxPause = -1
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		paused, err := sdk.limiter.wait(ctx, endpoint, maxPause)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit wait for %s: %w", endpoint, err)
+		}
+		if paused != nil {
+			return paused, nil
+		}
+
+		resp, err := sdk.httpClient.Do(req)
+
+		serverDelay, throttled := retryAfter(resp)
+		if throttled {
+			sdk.limiter.pause(endpoint, time.Now().Add(serverDelay), resp)
+		}
+
+		if policy == nil || attempt >= policy.MaxAttempts || !policy.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		// A server asking for a longer pause than MaxDelay gets its error returned instead
+		if policy.MaxDelay > 0 && serverDelay > policy.MaxDelay {
+			return resp, err
+		}
+
+		delay := max(policy.backoff(attempt), serverDelay)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+
+		if sleepContext(ctx, delay) != nil {
+			return resp, err
+		}
+	}
+}
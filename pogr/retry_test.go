@@ -0,0 +1,202 @@
+package pogr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+// fastRetries retries quickly so tests measure server-imposed delays only
+func fastRetries(config *pogr.Config) {
+	config.EnableRetries = true
+	config.RetryPolicy = &pogr.RetryPolicy{
+		MaxAttempts:          3,
+		BaseDelay:            time.Millisecond,
+		MaxDelay:             5 * time.Second,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+	}
+}
+
+func TestRetryRecoversFromTransientFailures(t *testing.T) {
+	srv, sdk := newTestClient(t, fastRetries)
+
+	srv.FailNext("/data", http.StatusServiceUnavailable, "down")
+	srv.FailNext("/data", http.StatusServiceUnavailable, "down")
+
+	dataID, err := sdk.SendData(map[string]int{"score": 1}, nil)
+	if err != nil {
+		t.Fatalf("SendData: %v", err)
+	}
+	if dataID == "" {
+		t.Error("SendData returned an empty data ID")
+	}
+	if got := len(srv.RequestsTo("/data")); got != 3 {
+		t.Errorf("got %d attempts, want 3", got)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	srv, sdk := newTestClient(t, fastRetries)
+
+	for range 3 {
+		srv.FailNext("/data", http.StatusServiceUnavailable, "down")
+	}
+
+	_, err := sdk.SendData(map[string]int{"score": 1}, nil)
+	var apiErr *pogr.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("got %v, want a 503 APIError", err)
+	}
+	if got := len(srv.RequestsTo("/data")); got != 3 {
+		t.Errorf("got %d attempts, want 3", got)
+	}
+}
+
+func TestRetryDoesNotRepeatClientErrors(t *testing.T) {
+	srv, sdk := newTestClient(t, fastRetries)
+
+	srv.FailNext("/data", http.StatusBadRequest, "bad payload")
+
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); !errors.Is(err, pogr.ErrInvalidData) {
+		t.Fatalf("got %v, want ErrInvalidData", err)
+	}
+	if got := len(srv.RequestsTo("/data")); got != 1 {
+		t.Errorf("got %d attempts, want 1", got)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	srv, sdk := newTestClient(t, fastRetries)
+
+	srv.RateLimitNext("/data", time.Second)
+
+	start := time.Now()
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); err != nil {
+		t.Fatalf("SendData: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+
+	requests := srv.RequestsTo("/data")
+	if len(requests) != 2 || requests[0].Status != http.StatusTooManyRequests || requests[1].Status != http.StatusOK {
+		t.Errorf("got %d requests, want a 429 followed by a 200", len(requests))
+	}
+}
+
+func TestRetryAfterBeyondMaxDelayReturnsError(t *testing.T) {
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		fastRetries(config)
+		config.RetryPolicy.MaxDelay = 100 * time.Millisecond
+	})
+
+	srv.RateLimitNext("/data", time.Hour)
+
+	start := time.Now()
+	_, err := sdk.SendData(map[string]int{"score": 1}, nil)
+	var apiErr *pogr.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("got %v, want the 429 APIError", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v, want no wait for a Retry-After above MaxDelay", elapsed)
+	}
+	if got := len(srv.RequestsTo("/data")); got != 1 {
+		t.Errorf("got %d attempts, want 1", got)
+	}
+}
+
+func TestServerPauseBeyondMaxDelayFailsFast(t *testing.T) {
+	srv, sdk := newTestClient(t, fastRetries)
+
+	srv.RateLimitNext("/data", time.Hour)
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); err == nil {
+		t.Fatal("SendData succeeded despite the 429")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := sdk.SendDataContext(ctx, map[string]int{"score": 2}, nil)
+	var apiErr *pogr.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("got %v, want the 429 APIError", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v, want no wait for a pause above MaxDelay", elapsed)
+	}
+	if retryAfter := apiErr.Headers["Retry-After"]; retryAfter == "" || retryAfter == "0" {
+		t.Errorf("got Retry-After %q, want the remaining pause", retryAfter)
+	}
+	if got := len(srv.RequestsTo("/data")); got != 1 {
+		t.Errorf("got %d requests, want the paused call not to reach the intake", got)
+	}
+}
+
+func TestServerPauseWithoutRetriesFailsFast(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	srv.RateLimitNext("/data", 2*time.Second)
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); err == nil {
+		t.Fatal("SendData succeeded despite the 429")
+	}
+
+	start := time.Now()
+	_, err := sdk.SendData(map[string]int{"score": 2}, nil)
+	var apiErr *pogr.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("got %v, want the 429 APIError", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("returned after %v, want no wait with retries disabled", elapsed)
+	}
+	if got := len(srv.RequestsTo("/data")); got != 1 {
+		t.Errorf("got %d requests, want the paused call not to reach the intake", got)
+	}
+}
+
+func TestServerPauseBeyondDeadlineFailsFast(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	srv.RateLimitNext("/data", 3*time.Second)
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); err == nil {
+		t.Fatal("SendData succeeded despite the 429")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := sdk.SendDataContext(ctx, map[string]int{"score": 2}, nil)
+	var apiErr *pogr.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("got %v, want the 429 APIError", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("returned after %v, want no wait for a pause outlasting the deadline", elapsed)
+	}
+}
+
+func TestRateLimitSpacesRequests(t *testing.T) {
+	_, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.RateLimits = map[string]pogr.RateLimit{
+			"/data": {RequestsPerSecond: 20, Burst: 1},
+		}
+	})
+
+	start := time.Now()
+	for range 3 {
+		if _, err := sdk.SendData(map[string]int{"score": 1}, nil); err != nil {
+			t.Fatalf("SendData: %v", err)
+		}
+	}
+	// The burst covers the first request; the other two wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests took %v, want at least 90ms at 20 requests per second", elapsed)
+	}
+}
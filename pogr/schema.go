@@ -0,0 +1,337 @@
+package pogr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// DataSender is implemented by the client and by sessions
+type DataSender interface {
+	SendDataContext(ctx context.Context, data interface{}, tags *Tags) (string, error)
+}
+
+// SendTyped validates value against the schema registered for T in DefaultSchemas,
+// or against T's pogr struct tags when none is registered, and sends it as data.
+// Only structs and pointers to structs have schemas; other types are sent unvalidated.
+func SendTyped[T any](ctx context.Context, sender DataSender, value T, tags *Tags) (string, error) {
+	typ := reflect.TypeFor[T]()
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return sender.SendDataContext(ctx, value, tags)
+	}
+
+	schema, err := schemaFor(DefaultSchemas, typ)
+	if err != nil {
+		return "", err
+	}
+	if err := schema.Validate(value); err != nil {
+		return "", err
+	}
+	return sender.SendDataContext(ctx, value, tags)
+}
+
+// ValidationError explains why a payload does not match its schema
+type ValidationError struct {
+	Schema string // Schema name
+	Field  string // Field path using JSON names, e.g. "player.name"
+	Rule   string // Failed rule: "required", "max" or "enum"
+	Reason string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("pogr: invalid %s.%s (%s): %s", e.Schema, e.Field, e.Rule, e.Reason)
+}
+
+// Unwrap makes errors.Is(err, ErrInvalidData) true
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidData
+}
+
+// Schema validates payloads of one struct type using its pogr struct tags:
+//
+//	Name  string `json:"name" pogr:"required,max=32"`
+//	Class string `json:"class" pogr:"enum=warrior|mage|rogue"`
+type Schema struct {
+	name   string
+	typ    reflect.Type
+	fields []fieldRule
+}
+
+// fieldRule holds the rules declared on one struct field
+type fieldRule struct {
+	index    int
+	name     string
+	required bool
+	max      int // -1 when unset
+	enum     []string
+	nested   []fieldRule // Rules of a struct or struct pointer field
+}
+
+// Name returns the schema name
+func (s *Schema) Name() string {
+	return s.name
+}
+
+// Validate checks value, which must be of the schema's type or a pointer to it,
+// and returns every violation joined together
+func (s *Schema) Validate(value any) error {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return fmt.Errorf("%w: %s payload is nil", ErrInvalidData, s.name)
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return fmt.Errorf("%w: %s payload is nil", ErrInvalidData, s.name)
+		}
+		v = v.Elem()
+	}
+	if v.Type() != s.typ {
+		return fmt.Errorf("%w: schema %s expects %s, got %s", ErrInvalidData, s.name, s.typ, v.Type())
+	}
+
+	var errs []error
+	s.validate(v, s.fields, "", &errs)
+	return errors.Join(errs...)
+}
+
+func (s *Schema) validate(v reflect.Value, rules []fieldRule, prefix string, errs *[]error) {
+	invalid := func(field, rule, reason string) {
+		*errs = append(*errs, &ValidationError{Schema: s.name, Field: field, Rule: rule, Reason: reason})
+	}
+
+	for _, rule := range rules {
+		field := v.Field(rule.index)
+		path := prefix + rule.name
+
+		if field.IsZero() {
+			if rule.required {
+				invalid(path, "required", "must be set")
+			}
+			// An empty struct value can still break the rules of its own fields
+			if rule.nested != nil && field.Kind() == reflect.Struct {
+				s.validate(field, rule.nested, path+".", errs)
+			}
+			continue
+		}
+
+		if rule.max >= 0 {
+			if n := length(field); n > rule.max {
+				invalid(path, "max", fmt.Sprintf("length %d exceeds %d", n, rule.max))
+			}
+		}
+
+		if len(rule.enum) > 0 && !slices.Contains(rule.enum, field.String()) {
+			invalid(path, "enum", fmt.Sprintf("%q is not one of %s", field.String(), strings.Join(rule.enum, ", ")))
+		}
+
+		if rule.nested != nil {
+			if field.Kind() == reflect.Pointer {
+				field = field.Elem()
+			}
+			s.validate(field, rule.nested, path+".", errs)
+		}
+	}
+}
+
+// length returns the number of characters in a string or elements in a collection
+func length(v reflect.Value) int {
+	if v.Kind() == reflect.String {
+		return utf8.RuneCountInString(v.String())
+	}
+	return v.Len()
+}
+
+// SchemaRegistry holds named schemas
+type SchemaRegistry struct {
+	mu     sync.RWMutex
+	byName map[string]*Schema
+	byType map[reflect.Type]*Schema
+}
+
+// DefaultSchemas is the registry used by SendTyped
+var DefaultSchemas = NewSchemaRegistry()
+
+// NewSchemaRegistry creates an empty schema registry
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		byName: make(map[string]*Schema),
+		byType: make(map[reflect.Type]*Schema),
+	}
+}
+
+// RegisterSchema compiles the pogr struct tags of T, which must be a struct or a pointer
+// to one, and registers the schema under name
+func RegisterSchema[T any](r *SchemaRegistry, name string) (*Schema, error) {
+	schema, err := compileSchema(name, reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.byName[name]; ok && existing.typ != schema.typ {
+		return nil, fmt.Errorf("schema %q is already registered for %s", name, existing.typ)
+	}
+	r.byName[name] = schema
+	r.byType[schema.typ] = schema
+	return schema, nil
+}
+
+// MustRegisterSchema is like RegisterSchema but panics on error, for use in package variables
+func MustRegisterSchema[T any](r *SchemaRegistry, name string) *Schema {
+	schema, err := RegisterSchema[T](r, name)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// Lookup returns the schema registered under name
+func (r *SchemaRegistry) Lookup(name string) (*Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	schema, ok := r.byName[name]
+	return schema, ok
+}
+
+// schemaFor returns the schema registered for a type, compiling an unnamed one if needed
+func schemaFor(r *SchemaRegistry, typ reflect.Type) (*Schema, error) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	r.mu.RLock()
+	schema, ok := r.byType[typ]
+	r.mu.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	schema, err := compileSchema(typ.Name(), typ)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache by type only, so the name stays free for RegisterSchema
+	r.mu.Lock()
+	if existing, ok := r.byType[typ]; ok {
+		schema = existing
+	} else {
+		r.byType[typ] = schema
+	}
+	r.mu.Unlock()
+	return schema, nil
+}
+
+// compileSchema parses the pogr struct tags of a struct type
+func compileSchema(name string, typ reflect.Type) (*Schema, error) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if name == "" {
+		name = typ.String()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema %s: %s is not a struct", name, typ)
+	}
+
+	fields, err := compileFields(typ, name, map[reflect.Type]bool{typ: true})
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{name: name, typ: typ, fields: fields}, nil
+}
+
+// compileFields parses the rules of every exported field, descending into nested structs
+func compileFields(typ reflect.Type, path string, visiting map[reflect.Type]bool) ([]fieldRule, error) {
+	var rules []fieldRule
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ","); jsonName == "-" {
+			continue
+		} else if jsonName != "" {
+			name = jsonName
+		}
+
+		rule := fieldRule{index: i, name: name, max: -1}
+		if err := parseRuleTag(field, &rule); err != nil {
+			return nil, fmt.Errorf("schema %s: invalid pogr tag on field %s: %w", path, field.Name, err)
+		}
+
+		nestedType := field.Type
+		if nestedType.Kind() == reflect.Pointer {
+			nestedType = nestedType.Elem()
+		}
+		if nestedType.Kind() == reflect.Struct && !visiting[nestedType] {
+			visiting[nestedType] = true
+			nested, err := compileFields(nestedType, path+"."+name, visiting)
+			delete(visiting, nestedType)
+			if err != nil {
+				return nil, err
+			}
+			if len(nested) > 0 {
+				rule.nested = nested
+			}
+		}
+
+		if rule.required || rule.max >= 0 || rule.enum != nil || rule.nested != nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// parseRuleTag reads a field's pogr tag into rule
+func parseRuleTag(field reflect.StructField, rule *fieldRule) error {
+	tag := field.Tag.Get("pogr")
+	if tag == "" {
+		return nil
+	}
+
+	for _, option := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "required":
+			rule.required = true
+		case "max":
+			switch field.Type.Kind() {
+			case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+			default:
+				return fmt.Errorf("max needs a string, slice, map or array, not %s", field.Type)
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("max=%s is not a non-negative integer", value)
+			}
+			rule.max = n
+		case "enum":
+			if field.Type.Kind() != reflect.String {
+				return fmt.Errorf("enum needs a string, not %s", field.Type)
+			}
+			if value == "" {
+				return errors.New("enum needs at least one value")
+			}
+			rule.enum = strings.Split(value, "|")
+		default:
+			return fmt.Errorf("unknown rule %q", key)
+		}
+	}
+	return nil
+}
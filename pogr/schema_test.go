@@ -0,0 +1,150 @@
+package pogr_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+type testLoadout struct {
+	Weapon string   `json:"weapon" pogr:"required"`
+	Perks  []string `json:"perks" pogr:"max=2"`
+}
+
+type testCharacter struct {
+	Name    string       `json:"name" pogr:"required,max=8"`
+	Class   string       `json:"class" pogr:"enum=warrior|mage|rogue"`
+	Level   int          `json:"level"`
+	Loadout *testLoadout `json:"loadout"`
+	Notes   string       `json:"-" pogr:"required"`
+}
+
+func TestSchemaValidatesTags(t *testing.T) {
+	registry := pogr.NewSchemaRegistry()
+	schema, err := pogr.RegisterSchema[testCharacter](registry, "character")
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	valid := testCharacter{Name: "Ayla", Class: "mage", Loadout: &testLoadout{Weapon: "staff"}}
+	if err := schema.Validate(valid); err != nil {
+		t.Errorf("Validate valid character: %v", err)
+	}
+	if err := schema.Validate(&valid); err != nil {
+		t.Errorf("Validate pointer to valid character: %v", err)
+	}
+
+	err = schema.Validate(testCharacter{
+		Name:    "Ayla the Bold",
+		Class:   "bard",
+		Loadout: &testLoadout{Perks: []string{"a", "b", "c"}},
+	})
+	if !errors.Is(err, pogr.ErrInvalidData) {
+		t.Fatalf("got %v, want ErrInvalidData", err)
+	}
+
+	violations := make(map[string]string)
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var validationErr *pogr.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("got %v, want a ValidationError", err)
+		}
+		violations[validationErr.Field] = validationErr.Rule
+	}
+	want := map[string]string{
+		"name":           "max",
+		"class":          "enum",
+		"loadout.weapon": "required",
+		"loadout.perks":  "max",
+	}
+	for field, rule := range want {
+		if violations[field] != rule {
+			t.Errorf("%s violated %q, want %q", field, violations[field], rule)
+		}
+	}
+	if len(violations) != len(want) {
+		t.Errorf("got violations %v, want %v", violations, want)
+	}
+
+	if err := schema.Validate(struct{ Name string }{"Ayla"}); !errors.Is(err, pogr.ErrInvalidData) {
+		t.Errorf("Validate with another type: got %v, want ErrInvalidData", err)
+	}
+}
+
+func TestRegisterSchemaRejectsBadTags(t *testing.T) {
+	registry := pogr.NewSchemaRegistry()
+
+	if _, err := pogr.RegisterSchema[struct {
+		Level int `pogr:"max=10"`
+	}](registry, "level"); err == nil {
+		t.Error("RegisterSchema accepted max on an int field")
+	}
+	if _, err := pogr.RegisterSchema[struct {
+		Class string `pogr:"oneof=a"`
+	}](registry, "class"); err == nil {
+		t.Error("RegisterSchema accepted an unknown rule")
+	}
+	if _, err := pogr.RegisterSchema[string](registry, "text"); err == nil {
+		t.Error("RegisterSchema accepted a non-struct type")
+	}
+
+	pogr.MustRegisterSchema[testCharacter](registry, "character")
+	if _, err := pogr.RegisterSchema[testLoadout](registry, "character"); err == nil {
+		t.Error("RegisterSchema reused a name for another type")
+	}
+	if schema, ok := registry.Lookup("character"); !ok || schema.Name() != "character" {
+		t.Errorf("Lookup(character) = %v, %v", schema, ok)
+	}
+}
+
+func TestSendTyped(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	_, err := pogr.SendTyped(context.Background(), sdk, testCharacter{Class: "mage"}, nil)
+	var validationErr *pogr.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "name" {
+		t.Errorf("got %v, want a ValidationError for name", err)
+	}
+	if !strings.Contains(err.Error(), "testCharacter") {
+		t.Errorf("error %q does not name the payload type", err)
+	}
+	if got := len(srv.RequestsTo("/data")); got != 0 {
+		t.Fatalf("got %d /data requests for an invalid payload, want 0", got)
+	}
+
+	dataID, err := pogr.SendTyped(context.Background(), sdk, testCharacter{Name: "Ayla", Class: "rogue", Level: 3}, nil)
+	if err != nil || dataID == "" {
+		t.Fatalf("SendTyped: %q, %v", dataID, err)
+	}
+	requests := srv.RequestsTo("/data")
+	if len(requests) != 1 {
+		t.Fatalf("got %d /data requests, want 1", len(requests))
+	}
+	data, _ := requests[0].Payload["data"].(map[string]interface{})
+	if data["name"] != "Ayla" || data["level"] != float64(3) {
+		t.Errorf("data = %v, want the character", requests[0].Payload["data"])
+	}
+}
+
+func TestSendTypedNonStruct(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+
+	if _, err := pogr.SendTyped(context.Background(), sdk, map[string]int{"score": 1}, nil); err != nil {
+		t.Fatalf("SendTyped with a map: %v", err)
+	}
+	requests := srv.RequestsTo("/data")
+	if len(requests) != 1 {
+		t.Fatalf("got %d /data requests, want 1", len(requests))
+	}
+	if data, _ := requests[0].Payload["data"].(map[string]interface{}); data["score"] != float64(1) {
+		t.Errorf("data = %v, want the map", requests[0].Payload["data"])
+	}
+
+	_, err := pogr.RegisterSchema[map[string]int](pogr.NewSchemaRegistry(), "")
+	if err == nil || !strings.Contains(err.Error(), "schema map[string]int: ") {
+		t.Errorf("RegisterSchema with a map: got %v, want an error naming the type", err)
+	}
+}
@@ -0,0 +1,438 @@
+package pogr
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// initRequest builds an /init request; it is kept so a session can be renewed with the same credentials
+type initRequest func(ctx context.Context) (*Request, error)
+
+// Session is an intake session sharing its client's HTTP transport, retries and pipelines
+type Session struct {
+	sdk *pogrSDK
+
+	mu       sync.RWMutex // Protects id, method, created, reinit, ended, ending and storeKey
+	id       string
+	method   InitMethod
+	created  time.Time
+	reinit   initRequest
+	ended    bool
+	ending   bool   // Set while End waits for the intake, so renewal does not revive the session
+	storeKey string // Key the session is saved under in Config.SessionStore, if any
+
+	heartbeat *heartbeat // Protected by mu
+
+	renewMu  sync.Mutex   // Serializes renewal
+	lastUsed atomic.Int64 // Unix nanoseconds of the last send
+}
+
+// claimFunc lets the owner of a new session, such as a SessionManager, accept it before it is
+// tracked. An error refuses the session.
+type claimFunc func(s *Session) error
+
+// newSession creates a session and tracks it so outbox replay can use it and Close can end it.
+// Once Close has started, or when claim refuses it, it returns the error along with the untracked session.
+func (sdk *pogrSDK) newSession(sessionID string, method InitMethod, reinit initRequest, claim claimFunc) (*Session, error) {
+	s := &Session{sdk: sdk, id: sessionID, method: method, created: time.Now(), reinit: reinit}
+	s.touch()
+
+	// Checked under the lock Close takes to collect sessions, so a session is either
+	// collected by Close or rejected here
+	sdk.mu.Lock()
+	defer sdk.mu.Unlock()
+	if sdk.closed.Load() {
+		return s, ErrClosed
+	}
+	if claim != nil {
+		if err := claim(s); err != nil {
+			return s, err
+		}
+	}
+	sdk.sessions[s] = struct{}{}
+
+	if sdk.config.EnableHeartbeat {
+		s.StartHeartbeat(sdk.config.HeartbeatConfig)
+	}
+	return s, nil
+}
+
+// runInit sends an /init request and returns the new session ID
+func (sdk *pogrSDK) runInit(ctx context.Context, build initRequest) (string, error) {
+	ctx, cancel := sdk.withTimeout(ctx)
+	defer cancel()
+
+	req, err := build(ctx)
+	if err != nil {
+		return "", err
+	}
+	return sdk.handleInitResponse(req)
+}
+
+// openSession starts a new session; claim may be nil
+func (sdk *pogrSDK) openSession(ctx context.Context, method InitMethod, build initRequest, claim claimFunc) (*Session, error) {
+	if sdk.closed.Load() {
+		return nil, ErrClosed
+	}
+	sessionID, err := sdk.runInit(ctx, build)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := sdk.newSession(sessionID, method, build, claim)
+	if err != nil {
+		// Close started during /init, or the owner refused the session, so nothing else will end it
+		session.End(ctx)
+		return nil, err
+	}
+	return session, nil
+}
+
+// lookupSession returns the tracked session with the given ID, or the session that renewed it.
+// Unknown IDs, such as those restored from the outbox after a restart, get a detached handle.
+func (sdk *pogrSDK) lookupSession(sessionID string) *Session {
+	if sessionID == "" {
+		return nil
+	}
+
+	sdk.mu.RLock()
+	defer sdk.mu.RUnlock()
+	for session := range sdk.sessions {
+		if session.ID() == sessionID {
+			return session
+		}
+	}
+	if session, ok := sdk.renamed[sessionID]; ok {
+		return session
+	}
+	return &Session{sdk: sdk, id: sessionID}
+}
+
+// rename records that a tracked session replaced an old session ID
+func (sdk *pogrSDK) rename(previousID string, s *Session) {
+	sdk.mu.Lock()
+	defer sdk.mu.Unlock()
+	if _, tracked := sdk.sessions[s]; tracked && previousID != "" {
+		sdk.renamed[previousID] = s
+	}
+}
+
+// untrack forgets an ended session and the IDs it replaced
+func (sdk *pogrSDK) untrack(s *Session) {
+	sdk.mu.Lock()
+	defer sdk.mu.Unlock()
+	delete(sdk.sessions, s)
+	for id, session := range sdk.renamed {
+		if session == s {
+			delete(sdk.renamed, id)
+		}
+	}
+}
+
+// ID returns the session ID
+func (s *Session) ID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.id
+}
+
+// Active reports whether the session can still be used
+func (s *Session) Active() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !s.ended && s.id != ""
+}
+
+// Method returns the initialization method that created the session
+func (s *Session) Method() InitMethod {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.method
+}
+
+// Created returns when the intake issued the session ID
+func (s *Session) Created() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.created
+}
+
+// LastUsed returns when the session last sent a payload
+func (s *Session) LastUsed() time.Time {
+	return time.Unix(0, s.lastUsed.Load())
+}
+
+// End ends the session with the intake
+func (s *Session) End(ctx context.Context) error {
+	s.mu.Lock()
+	sessionID, ended := s.id, s.ended
+	if !ended && sessionID != "" {
+		s.ending = true
+	}
+	s.mu.Unlock()
+
+	if ended || sessionID == "" {
+		return ErrNoActiveSession
+	}
+
+	err := s.sdk.endSession(ctx, sessionID)
+
+	s.mu.Lock()
+	s.ending = false
+	if err == nil && s.id == sessionID {
+		s.ended = true
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	s.StopHeartbeat()
+	s.sdk.untrack(s)
+	s.forget(ctx)
+	return nil
+}
+
+// endSession ends a session ID with the intake
+func (sdk *pogrSDK) endSession(ctx context.Context, sessionID string) error {
+	ctx, cancel := sdk.withTimeout(ctx)
+	defer cancel()
+
+	headers := map[string]string{
+		"INTAKE_SESSION_ID": sessionID,
+	}
+
+	req := &Request{
+		Method:  "POST",
+		URL:     fmt.Sprintf("%s/end", sdk.config.BaseURL),
+		Headers: headers,
+		Context: ctx,
+	}
+
+	return sdk.handleGenericResponse(req)
+}
+
+// SendData sends data with optional tags on this session
+func (s *Session) SendData(data interface{}, tags *Tags) (string, error) {
+	return s.SendDataContext(context.Background(), data, tags)
+}
+
+// SendDataContext is like SendData but uses ctx for cancellation and deadlines
+func (s *Session) SendDataContext(ctx context.Context, data interface{}, tags *Tags) (string, error) {
+	if err := s.use(); err != nil {
+		return "", err
+	}
+	return s.sdk.sendData(ctx, s, data, tags)
+}
+
+// SendEvent sends an event with relevant details and optional user tags on this session
+func (s *Session) SendEvent(event string, subEvent string, eventType string, eventFlag string, eventKey string, eventData map[string]interface{}, tags *Tags) (string, error) {
+	return s.SendEventContext(context.Background(), event, subEvent, eventType, eventFlag, eventKey, eventData, tags)
+}
+
+// SendEventContext is like SendEvent but uses ctx for cancellation and deadlines
+func (s *Session) SendEventContext(ctx context.Context, event string, subEvent string, eventType string, eventFlag string, eventKey string, eventData map[string]interface{}, tags *Tags) (string, error) {
+	if err := s.use(); err != nil {
+		return "", err
+	}
+	return s.sdk.sendEvent(ctx, s, &Event{
+		Event:     event,
+		SubEvent:  subEvent,
+		EventType: eventType,
+		EventFlag: eventFlag,
+		EventKey:  eventKey,
+		EventData: eventData,
+		Tags:      tags,
+	})
+}
+
+// SendEventObject validates and sends an event built with NewEvent on this session
+func (s *Session) SendEventObject(event *Event) (string, error) {
+	return s.SendEventObjectContext(context.Background(), event)
+}
+
+// SendEventObjectContext is like SendEventObject but uses ctx for cancellation and deadlines
+func (s *Session) SendEventObjectContext(ctx context.Context, event *Event) (string, error) {
+	if err := event.Validate(); err != nil {
+		return "", err
+	}
+	if err := s.use(); err != nil {
+		return "", err
+	}
+	return s.sdk.sendEvent(ctx, s, event)
+}
+
+// SendLog submits a log entry on this session
+func (s *Session) SendLog(service string, environment string, severity string, logType string, logMessage string, data map[string]interface{}, tags *Tags) (string, error) {
+	return s.SendLogContext(context.Background(), service, environment, severity, logType, logMessage, data, tags)
+}
+
+// SendLogContext is like SendLog but uses ctx for cancellation and deadlines
+func (s *Session) SendLogContext(ctx context.Context, service string, environment string, severity string, logType string, logMessage string, data map[string]interface{}, tags *Tags) (string, error) {
+	if err := s.use(); err != nil {
+		return "", err
+	}
+	return s.sdk.sendLog(ctx, s, service, environment, severity, logType, logMessage, data, tags)
+}
+
+// SendMetrics sends metrics on this session
+func (s *Session) SendMetrics(service string, environment string, metrics map[string]interface{}, tags *Tags) (string, error) {
+	return s.SendMetricsContext(context.Background(), service, environment, metrics, tags)
+}
+
+// SendMetricsContext is like SendMetrics but uses ctx for cancellation and deadlines
+func (s *Session) SendMetricsContext(ctx context.Context, service string, environment string, metrics map[string]interface{}, tags *Tags) (string, error) {
+	if err := s.use(); err != nil {
+		return "", err
+	}
+	return s.sdk.sendMetrics(ctx, s, service, environment, metrics, tags)
+}
+
+// SendMonitorData sends system resource usage data on this session
+func (s *Session) SendMonitorData(cpuUsage float64, memoryUsage int, dllsLoaded []string, settings map[string]interface{}) (string, error) {
+	return s.SendMonitorDataContext(context.Background(), cpuUsage, memoryUsage, dllsLoaded, settings)
+}
+
+// SendMonitorDataContext is like SendMonitorData but uses ctx for cancellation and deadlines
+func (s *Session) SendMonitorDataContext(ctx context.Context, cpuUsage float64, memoryUsage int, dllsLoaded []string, settings map[string]interface{}) (string, error) {
+	if err := s.use(); err != nil {
+		return "", err
+	}
+	return s.sdk.sendMonitorData(ctx, s, cpuUsage, memoryUsage, dllsLoaded, settings)
+}
+
+// use checks the session is active and records the send for idle tracking
+func (s *Session) use() error {
+	if !s.Active() {
+		return ErrNoActiveSession
+	}
+	s.touch()
+	return nil
+}
+
+func (s *Session) touch() {
+	s.lastUsed.Store(time.Now().UnixNano())
+}
+
+// authHeaders returns the headers authenticating a request on this session
+func (s *Session) authHeaders() (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.ended || s.id == "" {
+		return nil, ErrNoActiveSession
+	}
+	return map[string]string{"INTAKE_SESSION_ID": s.id}, nil
+}
+
+// replace points an active session at a new session ID and reports whether it did.
+// A session that is ended or being ended keeps its ID.
+func (s *Session) replace(sessionID string, method InitMethod, reinit initRequest) bool {
+	s.mu.Lock()
+	if s.ended || s.ending {
+		s.mu.Unlock()
+		return false
+	}
+	previousID := s.id
+	s.id, s.method, s.created, s.reinit = sessionID, method, time.Now(), reinit
+	s.mu.Unlock()
+
+	// Payloads queued under the old ID are delivered on this session
+	s.sdk.rename(previousID, s)
+	return true
+}
+
+// discard marks the session ended without contacting the intake
+func (s *Session) discard() {
+	s.mu.Lock()
+	s.ended = true
+	s.mu.Unlock()
+
+	s.StopHeartbeat()
+	s.sdk.untrack(s)
+}
+
+// adopt takes over the session ID and credentials of a replacement session
+func (s *Session) adopt(ctx context.Context, replacement *Session) error {
+	if replacement == nil || replacement == s {
+		return nil
+	}
+
+	replacement.mu.RLock()
+	sessionID, method, reinit, ended := replacement.id, replacement.method, replacement.reinit, replacement.ended
+	replacement.mu.RUnlock()
+	replacement.discard()
+
+	if ended {
+		return ErrNoActiveSession
+	}
+	if !s.replace(sessionID, method, reinit) {
+		s.abandon(ctx, sessionID)
+		return ErrNoActiveSession
+	}
+	return nil
+}
+
+// abandon ends a session ID that renewal opened but could not install, so it does not
+// linger on the intake. It runs even when ctx is cancelled, e.g. by End stopping the heartbeat.
+func (s *Session) abandon(ctx context.Context, sessionID string) {
+	// Best effort; the intake expires the session eventually either way
+	s.sdk.endSession(context.WithoutCancel(ctx), sessionID)
+}
+
+// renew replaces an expired session ID, unless another caller already did
+func (s *Session) renew(ctx context.Context, expiredID string) error {
+	s.renewMu.Lock()
+	defer s.renewMu.Unlock()
+
+	s.mu.RLock()
+	current, method, reinit, ended := s.id, s.method, s.reinit, s.ended || s.ending
+	s.mu.RUnlock()
+
+	if ended {
+		return ErrNoActiveSession
+	}
+	if current != expiredID {
+		return nil
+	}
+
+	var err error
+	switch {
+	case s.sdk.config.OnSessionExpired != nil:
+		var replacement *Session
+		if replacement, err = s.sdk.config.OnSessionExpired(ctx, s.sdk, s); err == nil {
+			err = s.adopt(ctx, replacement)
+		}
+	case reinit != nil:
+		var sessionID string
+		if sessionID, err = s.sdk.runInit(ctx, reinit); err == nil && !s.replace(sessionID, method, reinit) {
+			s.abandon(ctx, sessionID)
+			err = ErrNoActiveSession
+		}
+	default:
+		err = ErrNoActiveSession
+	}
+
+	if err == nil && s.ID() == expiredID {
+		err = ErrNoActiveSession
+	}
+	if err == nil {
+		// Best effort; a stale record only costs a failed Ping on the next resume
+		s.save(ctx)
+	}
+	if err != nil {
+		// Stop sending on the dead session
+		s.mu.RLock()
+		dead := s.id == expiredID
+		s.mu.RUnlock()
+
+		if dead {
+			s.discard()
+		}
+	}
+	return err
+}
@@ -0,0 +1,199 @@
+package pogr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+func TestSessionRenewsAfterExpiry(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+	defer sdk.Close(context.Background())
+
+	session, err := sdk.InitWithAssociationID("player-1")
+	if err != nil {
+		t.Fatalf("InitWithAssociationID: %v", err)
+	}
+	expired := session.ID()
+	srv.ExpireSession(expired)
+
+	if _, err := session.SendData(map[string]int{"score": 1}, nil); err != nil {
+		t.Fatalf("SendData on expired session: %v", err)
+	}
+
+	if session.ID() == expired || !session.Active() {
+		t.Fatalf("session is %q (active %v), want a new active session", session.ID(), session.Active())
+	}
+	if !srv.SessionActive(session.ID()) {
+		t.Errorf("renewed session %s is unknown to the intake", session.ID())
+	}
+
+	requests := srv.RequestsTo("/data")
+	if len(requests) != 2 || requests[0].Status != http.StatusUnauthorized || requests[1].SessionID != session.ID() {
+		t.Errorf("got %d /data requests, want a 401 followed by a retry on the renewed session", len(requests))
+	}
+	if got := len(srv.RequestsTo("/init")); got != 2 {
+		t.Errorf("got %d /init requests, want 2", got)
+	}
+}
+
+func TestSessionRenewsWithCallback(t *testing.T) {
+	var calls int
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.OnSessionExpired = func(ctx context.Context, svc pogr.POGRService, expired *pogr.Session) (*pogr.Session, error) {
+			calls++
+			return svc.InitWithUserJWT("fresh-token")
+		}
+	})
+	defer sdk.Close(context.Background())
+
+	session, err := sdk.InitWithAssociationID("player-1")
+	if err != nil {
+		t.Fatalf("InitWithAssociationID: %v", err)
+	}
+	srv.ExpireSession(session.ID())
+
+	if _, err := session.SendData(map[string]int{"score": 1}, nil); err != nil {
+		t.Fatalf("SendData on expired session: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("OnSessionExpired called %d times, want 1", calls)
+	}
+	if session.Method() != pogr.InitUserJWT {
+		t.Errorf("session method is %q, want the replacement's %q", session.Method(), pogr.InitUserJWT)
+	}
+}
+
+func TestSessionRenewalFailureIsReported(t *testing.T) {
+	errTokenService := errors.New("token service unavailable")
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.OnSessionExpired = func(ctx context.Context, svc pogr.POGRService, expired *pogr.Session) (*pogr.Session, error) {
+			return nil, errTokenService
+		}
+	})
+	defer sdk.Close(context.Background())
+
+	session, err := sdk.InitWithAssociationID("player-1")
+	if err != nil {
+		t.Fatalf("InitWithAssociationID: %v", err)
+	}
+	srv.ExpireSession(session.ID())
+
+	_, err = session.SendData(map[string]int{"score": 1}, nil)
+	if !errors.Is(err, pogr.ErrUnauthorized) || !errors.Is(err, errTokenService) {
+		t.Fatalf("got %v, want the 401 joined with the renewal failure", err)
+	}
+	var apiErr *pogr.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("got %v, want a 401 APIError", err)
+	}
+	if session.Active() {
+		t.Error("session still active after renewal failed")
+	}
+}
+
+func TestSessionEndedDuringRenewalEndsReplacement(t *testing.T) {
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.OnSessionExpired = func(ctx context.Context, svc pogr.POGRService, expired *pogr.Session) (*pogr.Session, error) {
+			replacement, err := svc.InitWithUserJWT("fresh-token")
+			if err != nil {
+				return nil, err
+			}
+			// The caller ends the session while its replacement is being opened
+			if err := expired.End(ctx); err != nil {
+				t.Errorf("End during renewal: %v", err)
+			}
+			return replacement, nil
+		}
+	})
+	defer sdk.Close(context.Background())
+
+	session, err := sdk.InitWithAssociationID("player-1")
+	if err != nil {
+		t.Fatalf("InitWithAssociationID: %v", err)
+	}
+	srv.FailNext("/data", http.StatusUnauthorized, "session expired or invalid")
+
+	if _, err := session.SendData(map[string]int{"score": 1}, nil); err == nil {
+		t.Fatal("SendData succeeded on a session ended during renewal")
+	}
+	assertReplacementEnded(t, srv)
+}
+
+func TestSessionEndedDuringReinitEndsReplacement(t *testing.T) {
+	var session *pogr.Session
+	var inits int
+	srv, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.Interceptors = []pogr.Interceptor{func(next pogr.HTTPClient) pogr.HTTPClient {
+			return pogr.HTTPClientFunc(func(req *pogr.Request) (*pogr.Response, error) {
+				// End the session as the renewal /init goes out
+				if strings.HasSuffix(req.URL, "/init") {
+					if inits++; inits == 2 {
+						if err := session.End(context.Background()); err != nil {
+							t.Errorf("End during renewal: %v", err)
+						}
+					}
+				}
+				return next.Do(req)
+			})
+		}}
+	})
+	defer sdk.Close(context.Background())
+
+	var err error
+	session, err = sdk.InitWithAssociationID("player-1")
+	if err != nil {
+		t.Fatalf("InitWithAssociationID: %v", err)
+	}
+	srv.FailNext("/data", http.StatusUnauthorized, "session expired or invalid")
+
+	if _, err := session.SendData(map[string]int{"score": 1}, nil); err == nil {
+		t.Fatal("SendData succeeded on a session ended during renewal")
+	}
+	assertReplacementEnded(t, srv)
+}
+
+// assertReplacementEnded checks that the session opened by a failed renewal was ended
+func assertReplacementEnded(t *testing.T, srv *pogrtest.Server) {
+	t.Helper()
+
+	inits := srv.RequestsTo("/init")
+	if len(inits) != 2 {
+		t.Fatalf("got %d /init requests, want 2", len(inits))
+	}
+	replacement := inits[1].SessionID
+	if srv.SessionActive(replacement) {
+		t.Errorf("replacement session %s still active on the intake", replacement)
+	}
+	if ends := srv.RequestsTo("/end"); len(ends) != 2 || ends[1].SessionID != replacement {
+		t.Errorf("got %d /end requests, want the original followed by the replacement", len(ends))
+	}
+}
+
+func TestSessionEnd(t *testing.T) {
+	srv, sdk := newTestClient(t, nil)
+	defer sdk.Close(context.Background())
+
+	session, err := sdk.InitWithUserJWT("token")
+	if err != nil {
+		t.Fatalf("InitWithUserJWT: %v", err)
+	}
+	if err := session.End(context.Background()); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+
+	if srv.SessionActive(session.ID()) || session.Active() {
+		t.Error("session still active after End")
+	}
+	if _, err := session.SendData(map[string]int{"score": 1}, nil); !errors.Is(err, pogr.ErrNoActiveSession) {
+		t.Errorf("SendData after End: got %v, want ErrNoActiveSession", err)
+	}
+	if err := session.End(context.Background()); !errors.Is(err, pogr.ErrNoActiveSession) {
+		t.Errorf("second End: got %v, want ErrNoActiveSession", err)
+	}
+}
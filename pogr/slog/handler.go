@@ -0,0 +1,395 @@
+// Package slog provides a log/slog Handler that ships records to the POGR logs endpoint.
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+)
+
+// HandlerOptions configures a Handler
+type HandlerOptions struct {
+	Service         string                               // Service name sent with every log
+	Environment     string                               // Environment name sent with every log
+	Type            string                               // Log type sent with every log, defaults to "slog"
+	Level           slog.Leveler                         // Minimum level to ship, defaults to slog.LevelInfo
+	AddSource       bool                                 // Include the source file and line in the log data
+	TagsFromContext func(ctx context.Context) *pogr.Tags // Extracts user tags from the logging context
+	BufferSize      int                                  // Maximum buffered records before new ones are dropped
+	BatchSize       int                                  // Buffered records that trigger an immediate flush
+	FlushInterval   time.Duration                        // Maximum time a record waits before being shipped
+	OnError         func(err error)                      // Called when a record cannot be shipped
+}
+
+// Handler is a slog.Handler that ships records to POGR without blocking the caller
+type Handler struct {
+	shipper *shipper
+	opts    *HandlerOptions
+	attrs   map[string]interface{}
+	groups  []string
+}
+
+// NewHandler creates a Handler that sends records through the given service
+func NewHandler(sdk pogr.POGRService, opts *HandlerOptions) *Handler {
+	settings := HandlerOptions{
+		Type:          "slog",
+		Level:         slog.LevelInfo,
+		BufferSize:    1000,
+		BatchSize:     50,
+		FlushInterval: time.Second,
+	}
+	if opts != nil {
+		settings.Service = opts.Service
+		settings.Environment = opts.Environment
+		settings.AddSource = opts.AddSource
+		settings.TagsFromContext = opts.TagsFromContext
+		settings.OnError = opts.OnError
+		if opts.Type != "" {
+			settings.Type = opts.Type
+		}
+		if opts.Level != nil {
+			settings.Level = opts.Level
+		}
+		if opts.BufferSize > 0 {
+			settings.BufferSize = opts.BufferSize
+		}
+		if opts.BatchSize > 0 {
+			settings.BatchSize = opts.BatchSize
+		}
+		if opts.FlushInterval > 0 {
+			settings.FlushInterval = opts.FlushInterval
+		}
+	}
+
+	return &Handler{
+		shipper: newShipper(sdk, &settings),
+		opts:    &settings,
+		attrs:   make(map[string]interface{}),
+	}
+}
+
+// Enabled reports whether records at the given level are shipped
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle converts a record and queues it for shipping
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	data := cloneAttrs(h.attrs)
+	recordAttrs := make(map[string]interface{})
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(recordAttrs, attr)
+		return true
+	})
+	if len(recordAttrs) > 0 {
+		// Open groups only appear once a record puts attributes in them
+		mergeAttrs(groupMap(data, h.groups), recordAttrs)
+	}
+
+	if h.opts.AddSource && record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		if frame.File != "" {
+			data["source"] = fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+	}
+
+	var tags pogr.Tags
+	if h.opts.TagsFromContext != nil {
+		if ctxTags := h.opts.TagsFromContext(ctx); ctxTags != nil {
+			tags = *ctxTags
+		}
+	}
+	if tags.OverrideTimestamp == "" && !record.Time.IsZero() {
+		tags.OverrideTimestamp = record.Time.Format(time.RFC3339Nano)
+	}
+
+	h.shipper.enqueue(entry{
+		severity: Severity(record.Level),
+		message:  record.Message,
+		data:     data,
+		tags:     &tags,
+	})
+	return nil
+}
+
+// WithAttrs returns a Handler that adds the given attributes to every record
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	added := make(map[string]interface{})
+	for _, attr := range attrs {
+		addAttr(added, attr)
+	}
+	if len(added) == 0 {
+		return h
+	}
+
+	clone := *h
+	clone.attrs = cloneAttrs(h.attrs)
+	mergeAttrs(groupMap(clone.attrs, h.groups), added)
+	return &clone
+}
+
+// WithGroup returns a Handler that nests subsequent attributes under the group
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.groups = append(slices.Clip(h.groups), name)
+	return &clone
+}
+
+// Flush ships every buffered record and waits for completion
+func (h *Handler) Flush(ctx context.Context) error {
+	return h.shipper.flush(ctx)
+}
+
+// Close ships every buffered record and stops the background worker
+func (h *Handler) Close(ctx context.Context) error {
+	return h.shipper.close(ctx)
+}
+
+// Dropped returns how many records were discarded because the buffer was full
+func (h *Handler) Dropped() uint64 {
+	return h.shipper.dropped.Load()
+}
+
+// Severity maps a slog level to a POGR log severity
+func Severity(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "debug"
+	case level < slog.LevelWarn:
+		return "info"
+	case level < slog.LevelError:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// addAttr stores an attribute in the data map, expanding groups into nested maps
+func addAttr(data map[string]interface{}, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+		if len(group) == 0 {
+			return
+		}
+		if attr.Key == "" {
+			for _, member := range group {
+				addAttr(data, member)
+			}
+			return
+		}
+		nested := make(map[string]interface{})
+		for _, member := range group {
+			addAttr(nested, member)
+		}
+		if len(nested) > 0 {
+			mergeAttrs(groupMap(data, []string{attr.Key}), nested)
+		}
+		return
+	}
+
+	data[attr.Key] = attrValue(attr.Value)
+}
+
+// attrValue converts a resolved slog value into a JSON-friendly value
+func attrValue(value slog.Value) interface{} {
+	switch value.Kind() {
+	case slog.KindTime:
+		return value.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		return value.Duration().String()
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return err.Error()
+		}
+	}
+	return value.Any()
+}
+
+// groupMap returns the nested map for a group path, creating it as needed
+func groupMap(data map[string]interface{}, groups []string) map[string]interface{} {
+	for _, name := range groups {
+		nested, ok := data[name].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{})
+			data[name] = nested
+		}
+		data = nested
+	}
+	return data
+}
+
+// mergeAttrs copies src into dst, merging groups present in both
+func mergeAttrs(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if nested, ok := value.(map[string]interface{}); ok {
+			mergeAttrs(groupMap(dst, []string{key}), nested)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// cloneAttrs deep-copies nested group maps so handlers never share them
+func cloneAttrs(data map[string]interface{}) map[string]interface{} {
+	clone := maps.Clone(data)
+	if clone == nil {
+		clone = make(map[string]interface{})
+	}
+	for key, value := range clone {
+		if nested, ok := value.(map[string]interface{}); ok {
+			clone[key] = cloneAttrs(nested)
+		}
+	}
+	return clone
+}
+
+// entry is a converted record waiting to be shipped
+type entry struct {
+	severity string
+	message  string
+	data     map[string]interface{}
+	tags     *pogr.Tags
+}
+
+// shipper buffers entries and sends them from a background worker
+type shipper struct {
+	sdk     pogr.POGRService
+	opts    *HandlerOptions
+	entries chan entry
+	flushes chan chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+func newShipper(sdk pogr.POGRService, opts *HandlerOptions) *shipper {
+	s := &shipper{
+		sdk:     sdk,
+		opts:    opts,
+		entries: make(chan entry, opts.BufferSize),
+		flushes: make(chan chan struct{}),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// enqueue buffers an entry, dropping it if the buffer is full or the shipper is closed
+func (s *shipper) enqueue(e entry) {
+	select {
+	case <-s.stop:
+		s.dropped.Add(1)
+		return
+	default:
+	}
+
+	select {
+	case s.entries <- e:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+func (s *shipper) flush(ctx context.Context) error {
+	done := make(chan struct{})
+	select {
+	case s.flushes <- done:
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *shipper) close(ctx context.Context) error {
+	s.once.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run ships entries by batch size or interval until stopped
+func (s *shipper) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]entry, 0, s.opts.BatchSize)
+	for {
+		select {
+		case e := <-s.entries:
+			batch = append(batch, e)
+			if len(batch) >= s.opts.BatchSize {
+				s.send(batch)
+				batch = batch[:0]
+			}
+
+		case <-ticker.C:
+			s.send(batch)
+			batch = batch[:0]
+
+		case done := <-s.flushes:
+			batch = s.drain(batch)
+			s.send(batch)
+			batch = batch[:0]
+			close(done)
+
+		case <-s.stop:
+			s.send(s.drain(batch))
+			return
+		}
+	}
+}
+
+// drain moves every currently buffered entry into the batch
+func (s *shipper) drain(batch []entry) []entry {
+	for {
+		select {
+		case e := <-s.entries:
+			batch = append(batch, e)
+		default:
+			return batch
+		}
+	}
+}
+
+// send ships a batch entry by entry
+func (s *shipper) send(batch []entry) {
+	for _, e := range batch {
+		_, err := s.sdk.SendLogContext(context.Background(), s.opts.Service, s.opts.Environment, e.severity, s.opts.Type, e.message, e.data, e.tags)
+		if err != nil && s.opts.OnError != nil {
+			s.opts.OnError(err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package slog_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+	pogrslog "github.com/pogrio/golang_sdk/pogr/slog"
+)
+
+// newTestHandler starts a fake intake and a Handler shipping to it
+func newTestHandler(t *testing.T, opts *pogrslog.HandlerOptions) (*pogrtest.Server, *pogrslog.Handler) {
+	t.Helper()
+
+	srv := pogrtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	handler := pogrslog.NewHandler(pogr.NewPOGRSDK(srv.Config()), opts)
+	t.Cleanup(func() { handler.Close(context.Background()) })
+	return srv, handler
+}
+
+func flush(t *testing.T, handler *pogrslog.Handler) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := handler.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+}
+
+func TestHandlerConformsToSlog(t *testing.T) {
+	var srv *pogrtest.Server
+	var handler *pogrslog.Handler
+
+	slogtest.Run(t, func(t *testing.T) slog.Handler {
+		srv, handler = newTestHandler(t, nil)
+		return handler
+	}, func(t *testing.T) map[string]any {
+		flush(t, handler)
+
+		requests := srv.RequestsTo("/logs")
+		if len(requests) != 1 {
+			t.Fatalf("got %d /logs requests, want 1", len(requests))
+		}
+		payload := requests[0].Payload
+
+		// Rebuild the record from the log payload in the shape slogtest expects
+		result, _ := payload["data"].(map[string]any)
+		if result == nil {
+			result = make(map[string]any)
+		}
+		result[slog.LevelKey] = payload["severity"]
+		result[slog.MessageKey] = payload["log"]
+		if tags, ok := payload["tags"].(map[string]any); ok && tags["override_timestamp"] != nil {
+			result[slog.TimeKey] = tags["override_timestamp"]
+		}
+		return result
+	})
+}
+
+func TestHandlerShipsRecords(t *testing.T) {
+	type tagsKey struct{}
+	srv, handler := newTestHandler(t, &pogrslog.HandlerOptions{
+		Service:     "matchmaker",
+		Environment: "prod",
+		Level:       slog.LevelWarn,
+		TagsFromContext: func(ctx context.Context) *pogr.Tags {
+			tags, _ := ctx.Value(tagsKey{}).(*pogr.Tags)
+			return tags
+		},
+	})
+	logger := slog.New(handler).With("region", "eu").WithGroup("match")
+
+	ctx := context.WithValue(context.Background(), tagsKey{}, &pogr.Tags{AssociationID: "player-1"})
+	logger.InfoContext(ctx, "below the level")
+	logger.WarnContext(ctx, "queue slow", "wait", 3*time.Second, "err", errors.New("timeout"))
+	flush(t, handler)
+
+	requests := srv.RequestsTo("/logs")
+	if len(requests) != 1 {
+		t.Fatalf("got %d /logs requests, want 1", len(requests))
+	}
+	payload := requests[0].Payload
+	want := map[string]string{
+		"service":     "matchmaker",
+		"environment": "prod",
+		"severity":    "warning",
+		"type":        "slog",
+		"log":         "queue slow",
+	}
+	for field, value := range want {
+		if payload[field] != value {
+			t.Errorf("%s = %v, want %q", field, payload[field], value)
+		}
+	}
+
+	data, _ := payload["data"].(map[string]any)
+	match, _ := data["match"].(map[string]any)
+	if data["region"] != "eu" || match["wait"] != "3s" || match["err"] != "timeout" {
+		t.Errorf("data = %v, want region eu and match.wait 3s, match.err timeout", data)
+	}
+	if tags, _ := payload["tags"].(map[string]any); tags["association_id"] != "player-1" {
+		t.Errorf("tags = %v, want association_id player-1", payload["tags"])
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  string
+	}{
+		{slog.LevelDebug, "debug"},
+		{slog.LevelInfo, "info"},
+		{slog.LevelWarn, "warning"},
+		{slog.LevelError, "error"},
+		{slog.LevelError + 4, "error"},
+	}
+	for _, tt := range tests {
+		if got := pogrslog.Severity(tt.level); got != tt.want {
+			t.Errorf("Severity(%v) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestHandlerDropsWhenBufferIsFull(t *testing.T) {
+	srv, handler := newTestHandler(t, &pogrslog.HandlerOptions{BufferSize: 1, BatchSize: 1})
+
+	// Slow the intake so the worker is busy shipping while more records arrive
+	srv.SetLatency(50 * time.Millisecond)
+	logger := slog.New(handler)
+	for range 20 {
+		logger.Info("burst")
+	}
+
+	if handler.Dropped() == 0 {
+		t.Error("Dropped() = 0 after overflowing a one-record buffer")
+	}
+	flush(t, handler)
+	if got := uint64(len(srv.RequestsTo("/logs"))) + handler.Dropped(); got != 20 {
+		t.Errorf("shipped plus dropped = %d, want 20", got)
+	}
+}
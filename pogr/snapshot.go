@@ -0,0 +1,261 @@
+package pogr
+
+import "slices"
+
+// ConfigSnapshot is a JSON-friendly view of the effective configuration.
+// Credentials are masked unless explicitly revealed.
+type ConfigSnapshot struct {
+	BaseURL               string                       `json:"base_url"`
+	ClientKey             string                       `json:"client_key,omitempty"`
+	BuildKey              string                       `json:"build_key,omitempty"`
+	AccessKey             string                       `json:"access_key,omitempty"`
+	SecretKey             string                       `json:"secret_key,omitempty"`
+	SecretsRevealed       bool                         `json:"secrets_revealed"`
+	Timeout               string                       `json:"timeout"`
+	CustomHTTPClient      bool                         `json:"custom_http_client"`
+	Interceptors          int                          `json:"interceptors"`
+	VerifyResumedSessions bool                         `json:"verify_resumed_sessions"`
+	ConnectionPoolEnabled bool                         `json:"connection_pool_enabled"`
+	ConnectionPool        *ConnectionPoolSnapshot      `json:"connection_pool,omitempty"`
+	RetriesEnabled        bool                         `json:"retries_enabled"`
+	Retry                 *RetryPolicySnapshot         `json:"retry,omitempty"`
+	RateLimits            map[string]RateLimitSnapshot `json:"rate_limits,omitempty"`
+	AsyncEnabled          bool                         `json:"async_enabled"`
+	Async                 *AsyncSnapshot               `json:"async,omitempty"`
+	OutboxEnabled         bool                         `json:"outbox_enabled"`
+	Outbox                *OutboxSnapshot              `json:"outbox,omitempty"`
+	HeartbeatEnabled      bool                         `json:"heartbeat_enabled"`
+	Heartbeat             *HeartbeatSnapshot           `json:"heartbeat,omitempty"`
+	CompressionEnabled    bool                         `json:"compression_enabled"`
+	Compression           *CompressionSnapshot         `json:"compression,omitempty"`
+	BulkRequestsEnabled   bool                         `json:"bulk_requests_enabled"`
+	Batch                 *BatchSnapshot               `json:"batch,omitempty"`
+}
+
+// ConnectionPoolSnapshot describes connection pool settings
+type ConnectionPoolSnapshot struct {
+	MaxIdleConns        int    `json:"max_idle_conns"`
+	MaxIdleConnsPerHost int    `json:"max_idle_conns_per_host"`
+	MaxConnsPerHost     int    `json:"max_conns_per_host"`
+	IdleConnTimeout     string `json:"idle_conn_timeout"`
+}
+
+// RetryPolicySnapshot describes retry settings
+type RetryPolicySnapshot struct {
+	MaxAttempts          int     `json:"max_attempts"`
+	BaseDelay            string  `json:"base_delay"`
+	MaxDelay             string  `json:"max_delay"`
+	Jitter               float64 `json:"jitter"`
+	RetryableStatusCodes []int   `json:"retryable_status_codes"`
+	RetryNetworkErrors   bool    `json:"retry_network_errors"`
+	RetryTimeouts        bool    `json:"retry_timeouts"`
+}
+
+// RateLimitSnapshot describes a per-endpoint rate limit
+type RateLimitSnapshot struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+}
+
+// AsyncSnapshot describes async pipeline settings
+type AsyncSnapshot struct {
+	BufferSize    int    `json:"buffer_size"`
+	BatchSize     int    `json:"batch_size"`
+	FlushInterval string `json:"flush_interval"`
+}
+
+// OutboxSnapshot describes outbox settings
+type OutboxSnapshot struct {
+	Dir            string `json:"dir"`
+	MaxBytes       int64  `json:"max_bytes"`
+	MaxAge         string `json:"max_age"`
+	DropPolicy     string `json:"drop_policy"`
+	Durability     string `json:"durability"`
+	ReplayInterval string `json:"replay_interval"`
+}
+
+// HeartbeatSnapshot describes heartbeat settings
+type HeartbeatSnapshot struct {
+	Interval    string `json:"interval"`
+	MaxFailures int    `json:"max_failures"`
+}
+
+// CompressionSnapshot describes compression settings
+type CompressionSnapshot struct {
+	Encoding string `json:"encoding"`
+	MinSize  int    `json:"min_size"`
+}
+
+// BatchSnapshot describes bulk request settings
+type BatchSnapshot struct {
+	Format          string `json:"format"`
+	MaxPayloadBytes int    `json:"max_payload_bytes"`
+	MaxItems        int    `json:"max_items"`
+}
+
+// Snapshot returns a JSON-friendly view of the configuration with defaults applied.
+// Credentials are masked unless revealSecrets is true.
+func (c Config) Snapshot(revealSecrets bool) ConfigSnapshot {
+	secret := maskSecret
+	if revealSecrets {
+		secret = func(value string) string { return value }
+	}
+
+	snapshot := ConfigSnapshot{
+		BaseURL:               c.BaseURL,
+		ClientKey:             secret(c.ClientKey),
+		BuildKey:              secret(c.BuildKey),
+		AccessKey:             secret(c.AccessKey),
+		SecretKey:             secret(c.SecretKey),
+		SecretsRevealed:       revealSecrets,
+		Timeout:               c.Timeout.String(),
+		CustomHTTPClient:      c.HTTPClient != nil,
+		Interceptors:          len(c.Interceptors),
+		VerifyResumedSessions: c.VerifyResumedSessions,
+		ConnectionPoolEnabled: c.EnableConnectionPool,
+		RetriesEnabled:        c.EnableRetries,
+		AsyncEnabled:          c.EnableAsync,
+		OutboxEnabled:         c.EnableOutbox,
+		HeartbeatEnabled:      c.EnableHeartbeat,
+		CompressionEnabled:    c.EnableCompression,
+		BulkRequestsEnabled:   c.EnableBulkRequests,
+	}
+
+	if c.EnableConnectionPool {
+		pool := c.PoolConfig
+		if pool == nil {
+			pool = DefaultPoolConfig()
+		}
+		snapshot.ConnectionPool = &ConnectionPoolSnapshot{
+			MaxIdleConns:        pool.MaxIdleConns,
+			MaxIdleConnsPerHost: pool.MaxIdleConnsPerHost,
+			MaxConnsPerHost:     pool.MaxConnsPerHost,
+			IdleConnTimeout:     pool.IdleConnTimeout.String(),
+		}
+	}
+
+	if c.EnableRetries {
+		policy := c.RetryPolicy
+		if policy == nil {
+			policy = DefaultRetryPolicy()
+		}
+		snapshot.Retry = &RetryPolicySnapshot{
+			MaxAttempts:          policy.MaxAttempts,
+			BaseDelay:            policy.BaseDelay.String(),
+			MaxDelay:             policy.MaxDelay.String(),
+			Jitter:               policy.Jitter,
+			RetryableStatusCodes: slices.Clone(policy.RetryableStatusCodes),
+			RetryNetworkErrors:   policy.RetryNetworkErrors,
+			RetryTimeouts:        policy.RetryTimeouts,
+		}
+	}
+
+	if len(c.RateLimits) > 0 {
+		snapshot.RateLimits = make(map[string]RateLimitSnapshot, len(c.RateLimits))
+		for endpoint, limit := range c.RateLimits {
+			snapshot.RateLimits[endpoint] = RateLimitSnapshot(limit)
+		}
+	}
+
+	if c.EnableAsync {
+		async := newAsyncPipelineConfig(c.AsyncConfig)
+		snapshot.Async = &AsyncSnapshot{
+			BufferSize:    async.BufferSize,
+			BatchSize:     async.BatchSize,
+			FlushInterval: async.FlushInterval.String(),
+		}
+	}
+
+	if c.EnableOutbox {
+		outbox := newOutboxConfig(c.OutboxConfig)
+		snapshot.Outbox = &OutboxSnapshot{
+			Dir:            outbox.Dir,
+			MaxBytes:       outbox.MaxBytes,
+			MaxAge:         outbox.MaxAge.String(),
+			DropPolicy:     outbox.DropPolicy.String(),
+			Durability:     outbox.Durability.String(),
+			ReplayInterval: outbox.ReplayInterval.String(),
+		}
+	}
+
+	if c.EnableHeartbeat {
+		heartbeat := newHeartbeatConfig(c.HeartbeatConfig)
+		snapshot.Heartbeat = &HeartbeatSnapshot{
+			Interval:    heartbeat.Interval.String(),
+			MaxFailures: heartbeat.MaxFailures,
+		}
+	}
+
+	if c.EnableCompression {
+		compression := newCompressionConfig(c.CompressionConfig)
+		snapshot.Compression = &CompressionSnapshot{
+			Encoding: compression.Compressor.Encoding(),
+			MinSize:  compression.MinSize,
+		}
+	}
+
+	if c.EnableBulkRequests {
+		batch := newBatchConfig(c.BatchConfig)
+		snapshot.Batch = &BatchSnapshot{
+			Format:          batch.Format.String(),
+			MaxPayloadBytes: batch.MaxPayloadBytes,
+			MaxItems:        batch.MaxItems,
+		}
+	}
+
+	return snapshot
+}
+
+// ConfigSnapshot returns a JSON-friendly view of the SDK configuration
+func (sdk *pogrSDK) ConfigSnapshot(revealSecrets bool) ConfigSnapshot {
+	snapshot := sdk.config.Snapshot(revealSecrets)
+	// NewPOGRSDK always installs a client, so only report one the caller supplied
+	snapshot.CustomHTTPClient = sdk.customHTTPClient
+	return snapshot
+}
+
+// maskSecret hides all but the last four characters of a credential
+func maskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= 8 {
+		return "****"
+	}
+	return "****" + value[len(value)-4:]
+}
+
+// String returns the policy name
+func (p DropPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop_oldest"
+	case DropNewest:
+		return "drop_newest"
+	}
+	return "unknown"
+}
+
+// String returns the durability name
+func (d Durability) String() string {
+	switch d {
+	case DurabilityNone:
+		return "none"
+	case DurabilityFile:
+		return "file"
+	case DurabilityFull:
+		return "full"
+	}
+	return "unknown"
+}
+
+// String returns the format name
+func (f BatchFormat) String() string {
+	switch f {
+	case BatchJSONArray:
+		return "json_array"
+	case BatchNDJSON:
+		return "ndjson"
+	}
+	return "unknown"
+}
@@ -0,0 +1,87 @@
+package pogr_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+var testSecrets = []string{pogrtest.ClientKey, pogrtest.BuildKey, pogrtest.AccessKey, pogrtest.SecretKey}
+
+func TestPrintConfigMasksSecrets(t *testing.T) {
+	_, sdk := newTestClient(t, nil)
+
+	printed := sdk.PrintConfig()
+	for _, secret := range testSecrets {
+		if strings.Contains(printed, secret) {
+			t.Errorf("PrintConfig leaks %q:\n%s", secret, printed)
+		}
+	}
+	if !strings.Contains(printed, "****") {
+		t.Errorf("PrintConfig shows no masked credentials:\n%s", printed)
+	}
+}
+
+func TestConfigSnapshotMasksUnlessRevealed(t *testing.T) {
+	_, sdk := newTestClient(t, nil)
+
+	masked, err := json.Marshal(sdk.ConfigSnapshot(false))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	for _, secret := range testSecrets {
+		if strings.Contains(string(masked), secret) {
+			t.Errorf("masked snapshot leaks %q: %s", secret, masked)
+		}
+	}
+
+	revealed := sdk.ConfigSnapshot(true)
+	if !revealed.SecretsRevealed || revealed.SecretKey != pogrtest.SecretKey || revealed.ClientKey != pogrtest.ClientKey {
+		t.Errorf("revealed snapshot = %+v, want the real credentials", revealed)
+	}
+}
+
+func TestConfigSnapshotAppliesDefaults(t *testing.T) {
+	_, sdk := newTestClient(t, func(config *pogr.Config) {
+		config.EnableRetries = true
+		config.EnableConnectionPool = true
+	})
+
+	snapshot := sdk.ConfigSnapshot(false)
+	defaults := pogr.DefaultRetryPolicy()
+	if snapshot.Retry == nil || snapshot.Retry.MaxAttempts != defaults.MaxAttempts || snapshot.Retry.BaseDelay != defaults.BaseDelay.String() {
+		t.Errorf("retry snapshot = %+v, want the default policy", snapshot.Retry)
+	}
+	if snapshot.ConnectionPool == nil || snapshot.ConnectionPool.MaxIdleConns != pogr.DefaultPoolConfig().MaxIdleConns {
+		t.Errorf("pool snapshot = %+v, want the default pool", snapshot.ConnectionPool)
+	}
+	if snapshot.Async != nil || snapshot.Outbox != nil || snapshot.Batch != nil {
+		t.Errorf("snapshot reports settings for disabled features: %+v", snapshot)
+	}
+	if snapshot.CustomHTTPClient {
+		t.Error("CustomHTTPClient = true for a client without an HTTPClient")
+	}
+}
+
+func TestConfigSnapshotBulkRequests(t *testing.T) {
+	snapshot := pogr.Config{EnableBulkRequests: true, BatchConfig: &pogr.BatchConfig{MaxItems: 50}}.Snapshot(false)
+	if snapshot.Batch == nil || snapshot.Batch.MaxItems != 50 || snapshot.Batch.MaxPayloadBytes != pogr.DefaultBatchConfig().MaxPayloadBytes {
+		t.Errorf("batch snapshot = %+v, want MaxItems 50 and the default size limit", snapshot.Batch)
+	}
+}
+
+func TestConfigSnapshotShortSecrets(t *testing.T) {
+	snapshot := pogr.Config{ClientKey: "abc", SecretKey: "secret-key-1234"}.Snapshot(false)
+	if snapshot.ClientKey != "****" {
+		t.Errorf("ClientKey = %q, want a short key fully masked", snapshot.ClientKey)
+	}
+	if snapshot.SecretKey != "****1234" {
+		t.Errorf("SecretKey = %q, want only the last four characters", snapshot.SecretKey)
+	}
+	if snapshot.BuildKey != "" {
+		t.Errorf("BuildKey = %q, want an unset key left empty", snapshot.BuildKey)
+	}
+}
@@ -0,0 +1,238 @@
+package pogr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// InitMethod identifies the Init* call that created a session
+type InitMethod string
+
+const (
+	InitUserJWT       InitMethod = "user_jwt"
+	InitAssociationID InitMethod = "association_id"
+	InitSteamTicket   InitMethod = "steam_ticket"
+)
+
+// StoredSession is what a SessionStore persists about a session
+type StoredSession struct {
+	SessionID string     `json:"session_id"`
+	Method    InitMethod `json:"method"`
+	Created   time.Time  `json:"created"`
+}
+
+// SessionStore persists sessions so they can be resumed after a restart.
+// Load returns ErrSessionNotFound when nothing is stored for key.
+type SessionStore interface {
+	Load(ctx context.Context, key string) (*StoredSession, error)
+	Save(ctx context.Context, key string, session StoredSession) error
+	Delete(ctx context.Context, key string) error
+}
+
+// ResumeSession reuses the session stored under key, otherwise it starts a new one with method
+// and credential and stores that instead. A stored session that has expired is renewed with the
+// same credential on its first rejected send. With Config.VerifyResumedSessions set, the stored
+// session is pinged first and replaced right away if the intake rejects it.
+func (sdk *pogrSDK) ResumeSession(ctx context.Context, key string, method InitMethod, credential string) (*Session, error) {
+	if sdk.closed.Load() {
+		return nil, ErrClosed
+	}
+
+	build, err := sdk.initRequestFor(method, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	store := sdk.config.SessionStore
+	if store == nil {
+		return sdk.openSession(ctx, method, build, nil)
+	}
+
+	stored, err := store.Load(ctx, key)
+	switch {
+	case err == nil && stored.Method == method && stored.SessionID != "":
+		session, err := sdk.newSession(stored.SessionID, method, build, nil)
+		if err != nil {
+			return nil, err
+		}
+		session.mu.Lock()
+		session.created, session.storeKey = stored.Created, key
+		session.mu.Unlock()
+
+		if !sdk.config.VerifyResumedSessions {
+			return session, nil
+		}
+
+		// Only a rejection proves the session dead; if the intake is unreachable the session
+		// is kept, and a 401 on a later send renews it with the same credential
+		if err := session.Ping(ctx); !errors.Is(err, ErrUnauthorized) {
+			return session, nil
+		}
+		session.discard()
+	case err != nil && !errors.Is(err, ErrSessionNotFound):
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	session, err := sdk.openSession(ctx, method, build, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mu.Lock()
+	session.storeKey = key
+	session.mu.Unlock()
+
+	if err := session.save(ctx); err != nil {
+		session.End(ctx)
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+	return session, nil
+}
+
+// initRequestFor returns the /init request builder for a method and its credential
+func (sdk *pogrSDK) initRequestFor(method InitMethod, credential string) (initRequest, error) {
+	switch method {
+	case InitUserJWT:
+		return sdk.userJWTInit(credential), nil
+	case InitAssociationID:
+		return sdk.associationIDInit(credential), nil
+	case InitSteamTicket:
+		return sdk.steamTicketInit(credential), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown init method %q", ErrInvalidData, method)
+	}
+}
+
+// Ping sends the heartbeat event (see HeartbeatConfig) on the session and fails with
+// ErrUnauthorized once the session has expired. It does not renew the session.
+func (s *Session) Ping(ctx context.Context) error {
+	return s.ping(ctx, newHeartbeatConfig(s.sdk.config.HeartbeatConfig).Event)
+}
+
+// ping sends event on the session without renewing it
+func (s *Session) ping(ctx context.Context, event *Event) error {
+	headers, err := s.authHeaders()
+	if err != nil {
+		return err
+	}
+	headers["Content-Type"] = "application/json"
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	ctx, cancel := s.sdk.withTimeout(ctx)
+	defer cancel()
+
+	req := &Request{
+		Method:  "POST",
+		URL:     fmt.Sprintf("%s/event", s.sdk.config.BaseURL),
+		Headers: headers,
+		Body:    payload,
+		Context: ctx,
+	}
+
+	return s.sdk.handleGenericResponse(req)
+}
+
+// save writes the session to the configured store, if it was opened with ResumeSession
+func (s *Session) save(ctx context.Context) error {
+	s.mu.RLock()
+	key := s.storeKey
+	stored := StoredSession{SessionID: s.id, Method: s.method, Created: s.created}
+	s.mu.RUnlock()
+
+	store := s.sdk.config.SessionStore
+	if store == nil || key == "" {
+		return nil
+	}
+	return store.Save(ctx, key, stored)
+}
+
+// forget removes an ended session from the configured store
+func (s *Session) forget(ctx context.Context) {
+	s.mu.RLock()
+	key := s.storeKey
+	s.mu.RUnlock()
+
+	if store := s.sdk.config.SessionStore; store != nil && key != "" {
+		store.Delete(ctx, key)
+	}
+}
+
+// FileSessionStore is a SessionStore keeping one JSON file per key in a directory
+type FileSessionStore struct {
+	dir string
+	mu  sync.Mutex // Serializes writes
+}
+
+// NewFileSessionStore creates a file-based session store in dir
+func NewFileSessionStore(dir string) *FileSessionStore {
+	return &FileSessionStore{dir: dir}
+}
+
+// Load reads the session stored under key
+func (f *FileSessionStore) Load(ctx context.Context, key string) (*StoredSession, error) {
+	data, err := os.ReadFile(f.pathFor(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var stored StoredSession
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode session file: %w", err)
+	}
+	return &stored, nil
+}
+
+// Save atomically writes the session under key
+func (f *FileSessionStore) Save(ctx context.Context, key string, session StoredSession) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.MkdirAll(f.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	path := f.pathFor(key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the session stored under key
+func (f *FileSessionStore) Delete(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.Remove(f.pathFor(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete session file: %w", err)
+	}
+	return nil
+}
+
+func (f *FileSessionStore) pathFor(key string) string {
+	return filepath.Join(f.dir, url.PathEscape(key)+".json")
+}
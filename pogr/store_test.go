@@ -0,0 +1,152 @@
+package pogr_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+// newStoreClient creates a client on srv that persists sessions in dir, as after a process restart
+func newStoreClient(srv *pogrtest.Server, dir string) pogr.POGRService {
+	config := srv.Config()
+	config.SessionStore = pogr.NewFileSessionStore(dir)
+	return pogr.NewPOGRSDK(config)
+}
+
+// newVerifyingStoreClient is like newStoreClient but pings stored sessions before reusing them
+func newVerifyingStoreClient(srv *pogrtest.Server, dir string) pogr.POGRService {
+	config := srv.Config()
+	config.SessionStore = pogr.NewFileSessionStore(dir)
+	config.VerifyResumedSessions = true
+	return pogr.NewPOGRSDK(config)
+}
+
+func TestResumeSessionReusesLiveSession(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	first, err := newStoreClient(srv, dir).ResumeSession(ctx, "player-1", pogr.InitAssociationID, "assoc-1")
+	if err != nil {
+		t.Fatalf("first ResumeSession: %v", err)
+	}
+
+	// The first client crashed without ending its session
+	resumed, err := newStoreClient(srv, dir).ResumeSession(ctx, "player-1", pogr.InitAssociationID, "assoc-1")
+	if err != nil {
+		t.Fatalf("second ResumeSession: %v", err)
+	}
+
+	if resumed.ID() != first.ID() {
+		t.Errorf("resumed session %q, want the stored %q", resumed.ID(), first.ID())
+	}
+	if got := len(srv.RequestsTo("/init")); got != 1 {
+		t.Errorf("got %d /init requests, want 1", got)
+	}
+	if got := len(srv.RequestsTo("/event")); got != 0 {
+		t.Errorf("got %d /event requests, want none without VerifyResumedSessions", got)
+	}
+}
+
+func TestResumeSessionVerifiesLiveSession(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	first, err := newStoreClient(srv, dir).ResumeSession(ctx, "player-1", pogr.InitAssociationID, "assoc-1")
+	if err != nil {
+		t.Fatalf("first ResumeSession: %v", err)
+	}
+
+	resumed, err := newVerifyingStoreClient(srv, dir).ResumeSession(ctx, "player-1", pogr.InitAssociationID, "assoc-1")
+	if err != nil {
+		t.Fatalf("second ResumeSession: %v", err)
+	}
+	if resumed.ID() != first.ID() {
+		t.Errorf("resumed session %q, want the stored %q", resumed.ID(), first.ID())
+	}
+	pings := srv.RequestsTo("/event")
+	if len(pings) != 1 || pings[0].Status != http.StatusOK || pings[0].SessionID != first.ID() {
+		t.Errorf("got %d /event requests, want one accepted ping on the stored session", len(pings))
+	}
+}
+
+func TestResumeSessionRenewsUnverifiedExpiredSession(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	first, err := newStoreClient(srv, dir).ResumeSession(ctx, "player-1", pogr.InitAssociationID, "assoc-1")
+	if err != nil {
+		t.Fatalf("first ResumeSession: %v", err)
+	}
+	srv.ExpireSession(first.ID())
+
+	resumed, err := newStoreClient(srv, dir).ResumeSession(ctx, "player-1", pogr.InitAssociationID, "assoc-1")
+	if err != nil {
+		t.Fatalf("second ResumeSession: %v", err)
+	}
+	if _, err := resumed.SendData(map[string]int{"score": 1}, nil); err != nil {
+		t.Fatalf("SendData on the expired session: %v", err)
+	}
+	if resumed.ID() == first.ID() || !srv.SessionActive(resumed.ID()) {
+		t.Errorf("session %q after the send, want it renewed", resumed.ID())
+	}
+}
+
+func TestResumeSessionReplacesExpiredSession(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	first, err := newStoreClient(srv, dir).ResumeSession(ctx, "player-1", pogr.InitAssociationID, "assoc-1")
+	if err != nil {
+		t.Fatalf("first ResumeSession: %v", err)
+	}
+	srv.ExpireSession(first.ID())
+
+	resumed, err := newVerifyingStoreClient(srv, dir).ResumeSession(ctx, "player-1", pogr.InitAssociationID, "assoc-1")
+	if err != nil {
+		t.Fatalf("second ResumeSession: %v", err)
+	}
+	if resumed.ID() == first.ID() || !srv.SessionActive(resumed.ID()) {
+		t.Errorf("resumed session %q, want a fresh active session", resumed.ID())
+	}
+
+	// The fresh session replaced the expired one in the store
+	again, err := newVerifyingStoreClient(srv, dir).ResumeSession(ctx, "player-1", pogr.InitAssociationID, "assoc-1")
+	if err != nil {
+		t.Fatalf("third ResumeSession: %v", err)
+	}
+	if again.ID() != resumed.ID() {
+		t.Errorf("resumed session %q, want the stored %q", again.ID(), resumed.ID())
+	}
+}
+
+func TestResumeSessionKeepsSessionWhileIntakeIsDown(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	first, err := newStoreClient(srv, dir).ResumeSession(ctx, "player-1", pogr.InitAssociationID, "assoc-1")
+	if err != nil {
+		t.Fatalf("first ResumeSession: %v", err)
+	}
+
+	srv.FailNext("/event", http.StatusServiceUnavailable, "down")
+	resumed, err := newVerifyingStoreClient(srv, dir).ResumeSession(ctx, "player-1", pogr.InitAssociationID, "assoc-1")
+	if err != nil {
+		t.Fatalf("second ResumeSession: %v", err)
+	}
+	if resumed.ID() != first.ID() {
+		t.Errorf("resumed session %q, want the stored %q kept", resumed.ID(), first.ID())
+	}
+}
@@ -0,0 +1,168 @@
+package pogr
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// NewPOGRSDKWithValidation validates the configuration before creating the SDK,
+// returning every problem found instead of failing on the first request
+func NewPOGRSDKWithValidation(config Config) (POGRService, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return NewPOGRSDK(config), nil
+}
+
+// Validate checks the configuration and returns a joined *ConfigError for every problem
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(field, value, reason string) {
+		errs = append(errs, &ConfigError{Field: field, Value: value, Reason: reason})
+	}
+
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		switch {
+		case err != nil:
+			invalid("BaseURL", c.BaseURL, fmt.Sprintf("does not parse: %v", err))
+		case u.Scheme != "http" && u.Scheme != "https":
+			invalid("BaseURL", c.BaseURL, "must use http or https")
+		case u.Host == "":
+			invalid("BaseURL", c.BaseURL, "has no host")
+		}
+	}
+
+	if c.ClientKey != "" && c.BuildKey == "" {
+		invalid("BuildKey", "", "required when ClientKey is set")
+	}
+	if c.BuildKey != "" && c.ClientKey == "" {
+		invalid("ClientKey", "", "required when BuildKey is set")
+	}
+	if c.AccessKey != "" && c.SecretKey == "" {
+		invalid("SecretKey", "", "required when AccessKey is set")
+	}
+	if c.SecretKey != "" && c.AccessKey == "" {
+		invalid("AccessKey", "", "required when SecretKey is set")
+	}
+	if c.ClientKey == "" && c.BuildKey == "" && c.AccessKey == "" && c.SecretKey == "" {
+		invalid("ClientKey", "", "no authentication method configured; set ClientKey and BuildKey or AccessKey and SecretKey")
+	}
+
+	if c.Timeout < 0 {
+		invalid("Timeout", c.Timeout.String(), "must not be negative")
+	}
+
+	if c.PoolConfig != nil {
+		pool := c.PoolConfig
+		if pool.MaxIdleConns < 0 {
+			invalid("PoolConfig.MaxIdleConns", fmt.Sprint(pool.MaxIdleConns), "must not be negative")
+		}
+		if pool.MaxIdleConnsPerHost < 0 {
+			invalid("PoolConfig.MaxIdleConnsPerHost", fmt.Sprint(pool.MaxIdleConnsPerHost), "must not be negative")
+		}
+		if pool.MaxConnsPerHost < 0 {
+			invalid("PoolConfig.MaxConnsPerHost", fmt.Sprint(pool.MaxConnsPerHost), "must not be negative")
+		}
+		if pool.MaxIdleConns > 0 && pool.MaxIdleConnsPerHost > pool.MaxIdleConns {
+			invalid("PoolConfig.MaxIdleConnsPerHost", fmt.Sprint(pool.MaxIdleConnsPerHost), "must not exceed MaxIdleConns")
+		}
+		if pool.MaxConnsPerHost > 0 && pool.MaxIdleConnsPerHost > pool.MaxConnsPerHost {
+			invalid("PoolConfig.MaxIdleConnsPerHost", fmt.Sprint(pool.MaxIdleConnsPerHost), "must not exceed MaxConnsPerHost")
+		}
+		if pool.IdleConnTimeout < 0 {
+			invalid("PoolConfig.IdleConnTimeout", pool.IdleConnTimeout.String(), "must not be negative")
+		}
+	}
+
+	if c.RetryPolicy != nil {
+		policy := c.RetryPolicy
+		if policy.MaxAttempts < 1 {
+			invalid("RetryPolicy.MaxAttempts", fmt.Sprint(policy.MaxAttempts), "must be at least 1")
+		}
+		if policy.BaseDelay < 0 {
+			invalid("RetryPolicy.BaseDelay", policy.BaseDelay.String(), "must not be negative")
+		}
+		if policy.MaxDelay < 0 {
+			invalid("RetryPolicy.MaxDelay", policy.MaxDelay.String(), "must not be negative")
+		}
+		if policy.MaxDelay > 0 && policy.MaxDelay < policy.BaseDelay {
+			invalid("RetryPolicy.MaxDelay", policy.MaxDelay.String(), "must not be less than BaseDelay")
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			invalid("RetryPolicy.Jitter", fmt.Sprint(policy.Jitter), "must be between 0 and 1")
+		}
+	}
+
+	for endpoint, limit := range c.RateLimits {
+		field := fmt.Sprintf("RateLimits[%q]", endpoint)
+		if !strings.HasPrefix(endpoint, "/") {
+			invalid(field, endpoint, `endpoint must start with "/", e.g. "/data"`)
+		}
+		if limit.RequestsPerSecond < 0 {
+			invalid(field+".RequestsPerSecond", fmt.Sprint(limit.RequestsPerSecond), "must not be negative")
+		}
+		if limit.Burst < 0 {
+			invalid(field+".Burst", fmt.Sprint(limit.Burst), "must not be negative")
+		}
+	}
+
+	if c.AsyncConfig != nil {
+		async := c.AsyncConfig
+		if async.BufferSize < 0 {
+			invalid("AsyncConfig.BufferSize", fmt.Sprint(async.BufferSize), "must not be negative")
+		}
+		if async.BatchSize < 0 {
+			invalid("AsyncConfig.BatchSize", fmt.Sprint(async.BatchSize), "must not be negative")
+		}
+		if async.FlushInterval < 0 {
+			invalid("AsyncConfig.FlushInterval", async.FlushInterval.String(), "must not be negative")
+		}
+	}
+
+	if c.EnableOutbox && (c.OutboxConfig == nil || c.OutboxConfig.Dir == "") {
+		invalid("OutboxConfig.Dir", "", "required when EnableOutbox is set")
+	}
+	if c.OutboxConfig != nil {
+		outbox := c.OutboxConfig
+		if outbox.ReplayInterval < 0 {
+			invalid("OutboxConfig.ReplayInterval", outbox.ReplayInterval.String(), "must not be negative")
+		}
+	}
+
+	if c.HeartbeatConfig != nil {
+		heartbeat := c.HeartbeatConfig
+		if heartbeat.Interval < 0 {
+			invalid("HeartbeatConfig.Interval", heartbeat.Interval.String(), "must not be negative")
+		}
+		if heartbeat.MaxFailures < 0 {
+			invalid("HeartbeatConfig.MaxFailures", fmt.Sprint(heartbeat.MaxFailures), "must not be negative")
+		}
+		if heartbeat.Event != nil {
+			if err := heartbeat.Event.Validate(); err != nil {
+				invalid("HeartbeatConfig.Event", heartbeat.Event.Event, err.Error())
+			}
+		}
+	}
+
+	if c.CompressionConfig != nil && c.CompressionConfig.MinSize < 0 {
+		invalid("CompressionConfig.MinSize", fmt.Sprint(c.CompressionConfig.MinSize), "must not be negative")
+	}
+
+	if c.BatchConfig != nil {
+		batch := c.BatchConfig
+		if batch.Format != BatchJSONArray && batch.Format != BatchNDJSON {
+			invalid("BatchConfig.Format", batch.Format.String(), "must be BatchJSONArray or BatchNDJSON")
+		}
+		if batch.MaxPayloadBytes < 0 {
+			invalid("BatchConfig.MaxPayloadBytes", fmt.Sprint(batch.MaxPayloadBytes), "must not be negative")
+		}
+		if batch.MaxItems < 0 {
+			invalid("BatchConfig.MaxItems", fmt.Sprint(batch.MaxItems), "must not be negative")
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,59 @@
+package pogr_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pogrio/golang_sdk/pogr"
+	"github.com/pogrio/golang_sdk/pogr/pogrtest"
+)
+
+func TestNewPOGRSDKWithValidationAcceptsValidConfig(t *testing.T) {
+	srv := pogrtest.NewServer()
+	defer srv.Close()
+
+	sdk, err := pogr.NewPOGRSDKWithValidation(srv.Config())
+	if err != nil {
+		t.Fatalf("NewPOGRSDKWithValidation: %v", err)
+	}
+	if _, err := sdk.SendData(map[string]int{"score": 1}, nil); err != nil {
+		t.Errorf("SendData: %v", err)
+	}
+}
+
+func TestNewPOGRSDKWithValidationListsEveryProblem(t *testing.T) {
+	sdk, err := pogr.NewPOGRSDKWithValidation(pogr.Config{
+		BaseURL:   "ftp://intake.example.com",
+		AccessKey: "access",
+		Timeout:   -time.Second,
+		PoolConfig: &pogr.ConnectionPoolConfig{
+			MaxIdleConns:        10,
+			MaxIdleConnsPerHost: 20,
+		},
+		RateLimits: map[string]pogr.RateLimit{"data": {RequestsPerSecond: 5, Burst: 1}},
+	})
+	if sdk != nil {
+		t.Error("got a client for an invalid config")
+	}
+
+	found := configErrors(t, err)
+	for _, field := range []string{"BaseURL", "SecretKey", "Timeout", "PoolConfig.MaxIdleConnsPerHost", `RateLimits["data"]`} {
+		if found[field] == nil {
+			t.Errorf("no error for %s in %v", field, err)
+		}
+	}
+	if len(found) != 5 {
+		t.Errorf("got %d problems, want 5: %v", len(found), err)
+	}
+}
+
+func TestValidateRequiresAuthentication(t *testing.T) {
+	found := configErrors(t, pogr.Config{}.Validate())
+	if found["ClientKey"] == nil || len(found) != 1 {
+		t.Errorf("got %v, want only the missing authentication method", found)
+	}
+
+	if err := (pogr.Config{ClientKey: "client", BuildKey: "build"}).Validate(); err != nil {
+		t.Errorf("Validate with client keys: %v", err)
+	}
+}